@@ -0,0 +1,121 @@
+// import-passes — выпуск пропусков по CSV-списку посетителей напрямую через сервис, без HTTP.
+// Формат файла и результата — как у POST /api/v1/passes:import. Принимается только CSV:
+// таблицу XLSX нужно сохранить из Excel как «CSV UTF-8».
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	icfg "github.com/vbncursed/vkr/issue-service/internal/config"
+	ih "github.com/vbncursed/vkr/issue-service/internal/http"
+	"github.com/vbncursed/vkr/issue-service/internal/importer"
+	"github.com/vbncursed/vkr/issue-service/internal/repo"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+}
+
+// run — импорт целиком; ошибки возвращаются, чтобы отложенные Close успели выполниться
+func run() error {
+	cfg := icfg.Load()
+	var dbURL, in, out, orgID, tz, actor string
+	var pickup bool
+	var pickupTTL time.Duration
+	flag.StringVar(&dbURL, "db", cfg.DatabaseURL, "database url")
+	flag.StringVar(&in, "in", "-", "CSV file with visitors (CSV only, save XLSX as CSV UTF-8), - for stdin")
+	flag.StringVar(&out, "out", "-", "result CSV file, - for stdout")
+	flag.StringVar(&orgID, "org", "", "org_id for rows without org_id column")
+	flag.StringVar(&tz, "tz", "UTC", "IANA time zone for times without offset")
+	flag.StringVar(&actor, "actor", "", "issuer checked against policy issuers")
+	flag.BoolVar(&pickup, "pickup", true, "issue pickup tokens")
+	flag.DurationVar(&pickupTTL, "pickup-ttl", importer.DefaultPickupTTL, "pickup token ttl, capped by pass exp (max 168h)")
+	flag.Parse()
+
+	if pickupTTL <= 0 || pickupTTL > importer.MaxPickupTTL {
+		return fmt.Errorf("pickup-ttl: must be in (0, %s]", importer.MaxPickupTTL)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "Local" {
+		return fmt.Errorf("tz: unknown time zone %q", tz)
+	}
+	var src io.Reader = os.Stdin
+	if in != "-" {
+		fh, err := os.Open(in)
+		if err != nil {
+			return fmt.Errorf("in: %w", err)
+		}
+		defer fh.Close()
+		src = fh
+	}
+	opts := importer.Options{OrgID: orgID, Location: loc, Actor: actor, Pickup: pickup, PickupTTL: pickupTTL}
+	f, err := importer.Parse(src, opts)
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	ctx := context.Background()
+	pool, err := repo.NewPool(ctx, dbURL)
+	if err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+	defer pool.Close()
+
+	if err := repo.RunMigrations(ctx, pool); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	store := repo.NewStore(pool)
+	svc := issvc.New(store, store, store, store, store, issvc.RealClock{}, issvc.JWSSigner{}, issvc.JWSVerifier{}, issvc.Options{
+		StatusListURL:  cfg.StatusListURL,
+		MaxTTL:         cfg.MaxTTL,
+		IdempotencyTTL: cfg.IdempotencyTTL,
+		StrictPolicies: cfg.StrictPolicies,
+	})
+	results, runErr := importer.Run(ctx, svc, f, opts, time.Now().UTC())
+	// в файл результата внутренние ошибки попадают без текста — он только в журнале
+	for _, r := range results {
+		if r.Err == nil || r.Err == runErr {
+			continue
+		}
+		if code, _ := ih.DescribeImportError(r.Err); code == "internal" {
+			log.Printf("line %d: %v", r.Line, r.Err)
+		}
+	}
+
+	if err := writeResult(out, results, f.Comma); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	issued, failed := importer.Counts(results)
+	log.Printf("import: issued=%d failed=%d", issued, failed)
+	if runErr != nil {
+		return fmt.Errorf("import stopped: %w", runErr)
+	}
+	return nil
+}
+
+// writeResult — файл результата в out ("-" — stdout); ошибка закрытия файла — тоже ошибка записи
+func writeResult(out string, results []importer.Result, comma rune) error {
+	if out == "-" {
+		return importer.WriteResult(os.Stdout, results, comma, ih.DescribeImportError)
+	}
+	dst, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := importer.WriteResult(dst, results, comma, ih.DescribeImportError); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -19,6 +19,8 @@ import (
 	icfg "github.com/vbncursed/vkr/issue-service/internal/config"
 	ih "github.com/vbncursed/vkr/issue-service/internal/http"
 	"github.com/vbncursed/vkr/issue-service/internal/repo"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+	"github.com/vbncursed/vkr/issue-service/internal/worker"
 )
 
 func main() {
@@ -37,7 +39,14 @@ func main() {
 		log.Fatalf("migrate: %v", err)
 	}
 
-	e := ih.Router(pool, cfg)
+	store := repo.NewStore(pool)
+	svc := issvc.New(store, store, store, store, store, issvc.RealClock{}, issvc.JWSSigner{}, issvc.JWSVerifier{}, issvc.Options{
+		StatusListURL:  cfg.StatusListURL,
+		MaxTTL:         cfg.MaxTTL,
+		IdempotencyTTL: cfg.IdempotencyTTL,
+		StrictPolicies: cfg.StrictPolicies,
+	})
+	e := ih.Router(pool, svc, cfg)
 
 	srv := &http.Server{
 		Addr:              cfg.Bind,
@@ -52,6 +61,14 @@ func main() {
 		}
 	}()
 
+	// фоновый перевод просроченных пропусков в Expired
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	sweepDone := make(chan struct{})
+	go func() {
+		defer close(sweepDone)
+		worker.NewSweeper(svc, cfg.SweepInterval).Run(workerCtx)
+	}()
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
@@ -59,4 +76,6 @@ func main() {
 	shutdownCtx, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel2()
 	_ = srv.Shutdown(shutdownCtx)
+	stopWorkers()
+	<-sweepDone
 }
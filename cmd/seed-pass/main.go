@@ -78,6 +78,9 @@ VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,'Active')`,
 	if err != nil {
 		log.Fatalf("insert pass: %v", err)
 	}
+	if _, err := pool.Exec(ctx, `INSERT INTO pass_zones (pass_id, zone_id) VALUES ($1, $2)`, passID, "A1"); err != nil {
+		log.Fatalf("insert pass zone: %v", err)
+	}
 	fmt.Println("Demo pass ID:", passID)
 	fmt.Println("Payload (JWS):", compact)
 }
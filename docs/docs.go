@@ -40,6 +40,76 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/keys/{kid}/compromise": {
+            "post": {
+                "description": "Помечает ключ compromised (исчезает из JWKS, verify отвечает unknown_key) и одной транзакцией отзывает все его Active/Suspended пропуска с причиной compromised. Если ключ был активным или reissue=true, активным становится новый ключ; с reissue=true действующим Active пропускам выпускаются замены (meta.replaces, replaces_id). Ответ — отчёт по затронутым пропускам.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "Компрометация ключа эмитента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key ID",
+                        "name": "kid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Инициатор",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompromiseKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompromiseKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
         "/healthz": {
             "get": {
                 "produces": [
@@ -60,7 +130,117 @@ const docTemplate = `{
             }
         },
         "/passes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Список пропусков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "policy_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "zone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Active|Suspended|Revoked|Expired",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Префикс subject_name",
+                        "name": "subject_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nbf \u003e= (RFC3339)",
+                        "name": "nbf_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nbf \u003c (RFC3339)",
+                        "name": "nbf_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exp \u003e= (RFC3339)",
+                        "name": "exp_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exp \u003c (RFC3339)",
+                        "name": "exp_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at \u003e= (RFC3339)",
+                        "name": "issued_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at \u003c (RFC3339)",
+                        "name": "issued_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1..200, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPassesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
             "post": {
+                "description": "Политика policy_id должна быть зарегистрирована в организации: она задаёт level и one_time по умолчанию, а нарушения её правил (TTL, зоны, обязательные attrs, attrs_schema) возвращаются списком в details с кодом 422: field — путь вида attrs.car.plate. С Idempotency-Key (в пределах org_id и X-Actor) повтор запроса в течение IDEMPOTENCY_TTL_H возвращает исходный ответ без нового пропуска; тот же ключ с другим телом — 422, пока исходный запрос выполняется — 409.",
                 "consumes": [
                     "application/json"
                 ],
@@ -72,6 +252,18 @@ const docTemplate = `{
                 ],
                 "summary": "Выпуск пропуска",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Инициатор; сверяется с issuers политики",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ повтора: тот же ключ с тем же телом вернёт исходный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Create pass",
                         "name": "request",
@@ -87,6 +279,12 @@ const docTemplate = `{
                         "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/dto.CreatePassResponse"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true, если ответ повторён по Idempotency-Key"
+                            }
                         }
                     },
                     "400": {
@@ -95,6 +293,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.APIError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -110,15 +326,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/passes/{id}/approve": {
-            "post": {
+        "/passes/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pickup"
+                    "passes"
                 ],
-                "summary": "Сгенерировать pickup-token",
+                "summary": "Получить пропуск",
                 "parameters": [
                     {
                         "type": "string",
@@ -132,17 +348,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ApproveResponse"
+                            "$ref": "#/definitions/dto.PassResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/http.APIError"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/http.APIError"
                         }
@@ -154,17 +370,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/passes/{id}/revoke": {
-            "post": {
+            },
+            "patch": {
+                "description": "Меняет zone_id, subject_name (holder_hint) и/или attrs, сохраняя id пропуска: подписывается новая версия payload (pass.version+1, новый pass.status.index), прежняя переносится в pass_versions. Verify отвечает на прежнюю версию reason=superseded, в списке статусов её индекс помечен отозванным. Доступно для Active и Suspended. Новые attrs проверяются по required_attrs и attrs_schema политики (422 с details).",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "passes"
                 ],
-                "summary": "Отзыв пропуска",
+                "summary": "Изменить пропуск",
                 "parameters": [
                     {
                         "type": "string",
@@ -172,13 +390,34 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Инициатор",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AmendPassRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RevokeResponse"
+                            "$ref": "#/definitions/dto.AmendPassResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
                         }
                     },
                     "404": {
@@ -193,43 +432,50 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.APIError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/http.APIError"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
                     }
                 }
             }
         },
-        "/pickup": {
+        "/passes/{id}/approve": {
             "post": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "pickup"
                 ],
-                "summary": "Получить payload по pickup-token",
+                "summary": "Сгенерировать pickup-token",
                 "parameters": [
                     {
-                        "description": "Pickup",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PickupRequest"
-                        }
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.PickupResponse"
+                            "$ref": "#/definitions/dto.ApproveResponse"
                         }
                     },
                     "400": {
@@ -238,6 +484,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.APIError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -247,70 +505,2049 @@ const docTemplate = `{
                 }
             }
         },
-        "/readyz": {
+        "/passes/{id}/qr": {
             "get": {
+                "description": "Compact JWS пропуска (как payload в GET /passes/{id}) в виде QR-кода. format — png (по умолчанию) или svg, size — сторона в пикселях (64..2048, по умолчанию 256), ecc — уровень коррекции L, M (по умолчанию), Q или H. Если JWS не помещается в QR-код на выбранном уровне — 422 qr_too_large.",
                 "produces": [
+                    "image/png",
+                    "image/svg+xml",
                     "application/json"
                 ],
                 "tags": [
-                    "meta"
+                    "passes"
+                ],
+                "summary": "QR-код пропуска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "png|svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сторона в пикселях",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "L|M|Q|H",
+                        "name": "ecc",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Readiness probe",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "QR-код",
                         "schema": {
-                            "$ref": "#/definitions/http.ReadyzResponse"
+                            "type": "file"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/http.APIError"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/{id}/redeem": {
+            "post": {
+                "description": "Атомарно записывает проход считывателя; повторный проход по одноразовому пропуску отклоняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Погасить пропуск (проход)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Redeem",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedeemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedeemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/{id}/reinstate": {
+            "post": {
+                "description": "Suspended → Active, если exp ещё не наступил.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Восстановить пропуск",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PassStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/{id}/renew": {
+            "post": {
+                "description": "Выпускает новый JWS с тем же содержимым и новым exp, подписанный активным ключом (meta.replaces — прежний id), и отзывает прежний пропуск с причиной superseded — одной транзакцией. Остаток проходов переносится. exp должен быть позже текущего и в пределах max_ttl_s политики (или MAX_TTL_H), иначе 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Продление пропуска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Инициатор",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Renew",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/{id}/revoke": {
+            "post": {
+                "description": "Тело необязательно; без reason причина — other. Инициатор берётся из заголовка X-Actor.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Отзыв пропуска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Инициатор отзыва",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Revoke",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/{id}/suspend": {
+            "post": {
+                "description": "Active → Suspended. JWS остаётся прежним; approve/pickup/redeem/verify отклоняют пропуск до reinstate.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Приостановить пропуск",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pass ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Инициатор",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PassStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes:batch": {
+            "post": {
+                "description": "До 500 элементов в формате POST /passes. Каждый элемент проверяется отдельно, все выпущенные подписываются одним активным ключом и записываются одной транзакцией. Ответ 200 содержит итог по каждому индексу: issued с payload или failed с ошибкой в формате APIError. С all_or_nothing=true ошибка любого элемента отменяет пакет: остальные элементы получают status skipped и код batch_aborted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Пакетный выпуск пропусков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Инициатор; сверяется с issuers политик",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Batch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchCreatePassesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchCreatePassesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes:export": {
+            "get": {
+                "description": "Все пропуска по фильтрам списка (org_id обязателен) в порядке выпуска, без пагинации: строки читаются из базы серверным курсором и сразу пишутся в ответ. format=csv (по умолчанию) — колонки id, org_id, policy_id, subject_name, zone_ids (через пробел), nbf, exp, one_time, max_uses, uses, version, status, issuer_key_id, created_at, revoked_at, revocation_reason, revocation_note, revoked_by, suspended_at, suspended_by, replaces_id; format=ndjson — по объекту GET /passes/{id} (с payload) на строку. Если выгрузка оборвалась после начала ответа, соединение разрывается — неполный файл не выглядит завершённым.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Выгрузка пропусков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv|ndjson",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "policy_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "zone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Active|Suspended|Revoked|Expired",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Префикс subject_name",
+                        "name": "subject_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nbf \u003e= (RFC3339)",
+                        "name": "nbf_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nbf \u003c (RFC3339)",
+                        "name": "nbf_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exp \u003e= (RFC3339)",
+                        "name": "exp_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exp \u003c (RFC3339)",
+                        "name": "exp_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at \u003e= (RFC3339)",
+                        "name": "issued_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at \u003c (RFC3339)",
+                        "name": "issued_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes:import": {
+            "post": {
+                "description": "Только CSV с заголовком (разделитель ',' или ';', UTF-8, BOM допустим) — телом text/csv или полем file в multipart/form-data; XLSX не поддерживается — сохраните таблицу как CSV UTF-8, иначе 400 invalid_import_file. Колонки: subject_name, zone (несколько — через пробел или ';'), nbf, exp, policy; необязательные org_id, max_uses, one_time, attrs (JSON-объект) и attr.\u003cимя\u003e (строковый атрибут). Даты — RFC3339 или YYYY-MM-DD HH:MM / DD.MM.YYYY HH:MM в поясе tz; дата без времени в exp — до конца дня. Каждая строка проверяется как POST /passes. Ответ — файл результата CSV с тем же разделителем: line, subject_name, status (issued|failed), pass_id, pickup_token, pickup_expires_at, error_code, error_message. Ошибки строк не мешают остальным; ошибка структуры файла — 400 invalid_import_file.",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Импорт списка посетителей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Инициатор; сверяется с issuers политик",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Организация строк без колонки org_id",
+                        "name": "org_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Пояс IANA для дат без смещения (по умолчанию UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Выдать pickup-токены (по умолчанию true)",
+                        "name": "pickup",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Срок pickup-токенов в часах, 1–168, не дольше exp пропуска (по умолчанию 24)",
+                        "name": "pickup_ttl_h",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV-файл (для multipart/form-data)",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл результата",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Import-Failed": {
+                                "type": "integer",
+                                "description": "Строк с ошибкой"
+                            },
+                            "X-Import-Issued": {
+                                "type": "integer",
+                                "description": "Выпущено пропусков"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes:revoke": {
+            "post": {
+                "description": "Отзывает все Active/Suspended пропуска организации, совпавшие по subject_name, zone_id, policy_id и/или issuer_key_id (точное сравнение), одной транзакцией. С dry_run=true ничего не меняет и возвращает найденные id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "Массовый отзыв пропусков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Инициатор отзыва",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Filter",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkRevokeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkRevokeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pickup": {
+            "post": {
+                "description": "С qr {format, size, ecc} в ответе есть и QR-код payload — data URI в qr (параметры как у GET /passes/{id}/qr). Если payload не поместился в QR-код, токен всё равно погашен: payload выдаётся, а qr_error = qr_too_large.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickup"
+                ],
+                "summary": "Получить payload по pickup-token",
+                "parameters": [
+                    {
+                        "description": "Pickup",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PickupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PickupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/policies": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "policies"
+                ],
+                "summary": "Список политик",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPoliciesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Политика задаёт разрешённые зоны (вместе с потомками), max_ttl_s вместо глобального MAX_TTL_H, one_time по умолчанию, обязательные attrs, level и список X-Actor, которым разрешён выпуск.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "policies"
+                ],
+                "summary": "Создать политику",
+                "parameters": [
+                    {
+                        "description": "Policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/policies/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "policies"
+                ],
+                "summary": "Получить политику",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью заменяет правила; id и организация неизменны, выпущенные пропуска не пересматриваются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "policies"
+                ],
+                "summary": "Изменить политику",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Только политику без Active/Suspended пропусков.",
+                "tags": [
+                    "policies"
+                ],
+                "summary": "Удалить политику",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meta"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ReadyzResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/revocations": {
+            "get": {
+                "description": "payload — compact JWS (активный ключ эмитента) с отозванными, но ещё не истёкшими пропусками.\nsince=0 — полный список; since=N — дельта событий после версии N. version монотонно растёт.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revocations"
+                ],
+                "summary": "Список отзывов (CRL)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Org ID (без него — по всем организациям)",
+                        "name": "org_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Версия, полученная ранее",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevocationListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/status-list": {
+            "get": {
+                "description": "payload — compact JWS с base64url(gzip(bits)); бит pass.status.index = 1 — пропуск отозван или приостановлен (после reinstate бит снова 0).\nАдрес совпадает с pass.status.list в payload пропуска (STATUS_LIST_URL).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revocations"
+                ],
+                "summary": "Список статусов (bitstring)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatusListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify": {
+            "post": {
+                "description": "Проверяет подпись по issuer_keys (active/retired), статус в БД и окно nbf/exp с допуском VERIFY_SKEW_S.\nНевалидный пропуск — это 200 с valid=false и кодом reason.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verify"
+                ],
+                "summary": "Проверить пропуск",
+                "parameters": [
+                    {
+                        "description": "Verify",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/zones": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Список зон",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только прямые потомки зоны",
+                        "name": "parent_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListZonesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Иерархия building \u003e floor \u003e room: у здания нет parent_id, у этажа родитель — здание, у комнаты — этаж той же организации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Создать зону",
+                "parameters": [
+                    {
+                        "description": "Zone",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ZoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/zones/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Получить зону",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ZoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Только зону без дочерних зон, без Active/Suspended пропусков и не перечисленную в allowed_zones политик (иначе 409 zone_in_use).",
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Удалить зону",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Меняет name и/или parent_id; уровень и организация зоны неизменны.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Изменить зону",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Org ID",
+                        "name": "org_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ZoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.APIError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AmendPassRequest": {
+            "type": "object",
+            "properties": {
+                "attrs": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "expand_zones": {
+                    "type": "boolean"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AmendPassResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "issuer_key_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ApproveResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pickup_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BatchCreatePassesRequest": {
+            "type": "object",
+            "properties": {
+                "all_or_nothing": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CreatePassRequest"
+                    }
+                }
+            }
+        },
+        "dto.BatchCreatePassesResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "issued": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BatchItemResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BatchItemError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/dto.BatchItemError"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "issuer_key_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BulkRevokeRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "issuer_key_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BulkRevokeResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "revoked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CompromiseKeyRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "reissue": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CompromiseKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key_id": {
+                    "type": "string"
+                },
+                "new_key_id": {
+                    "type": "string"
+                },
+                "passes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CompromisedPassResponse"
+                    }
+                },
+                "reissued": {
+                    "type": "integer"
+                },
+                "revoked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CompromisedPassResponse": {
+            "type": "object",
+            "properties": {
+                "exp": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "previous_status": {
+                    "type": "string"
+                },
+                "replacement_id": {
+                    "type": "string"
+                },
+                "replacement_payload": {
+                    "type": "string"
+                },
+                "subject_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePassRequest": {
+            "type": "object",
+            "properties": {
+                "attrs": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "exp": {
+                    "type": "string"
+                },
+                "expand_zones": {
+                    "type": "boolean"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "nbf": {
+                    "type": "string"
+                },
+                "one_time": {
+                    "type": "boolean"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/models.Schedule"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreatePassResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "issuer_key_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePolicyRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_zones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attrs_schema": {
+                    "type": "object"
+                },
+                "default_one_time": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level": {
+                    "type": "string"
+                },
+                "max_ttl_s": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "required_attrs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateZoneRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JWK"
+                    }
+                }
+            }
+        },
+        "dto.ListPassesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PassResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ListPoliciesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PolicyResponse"
+                    }
+                }
+            }
+        },
+        "dto.ListZonesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ZoneResponse"
+                    }
+                }
+            }
+        },
+        "dto.PassResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuer_key_id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "nbf": {
+                    "type": "string"
+                },
+                "one_time": {
+                    "type": "boolean"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "replaces_id": {
+                    "type": "string"
+                },
+                "revocation_note": {
+                    "type": "string"
+                },
+                "revocation_reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "revoked_by": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/models.Schedule"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "suspended_by": {
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.ApproveResponse": {
+        },
+        "dto.PassStatusResponse": {
             "type": "object",
             "properties": {
-                "expires_at": {
+                "id": {
                     "type": "string"
                 },
-                "id": {
+                "status": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.PickupRequest": {
+            "type": "object",
+            "properties": {
+                "qr": {
+                    "description": "QR — вернуть вместе с payload QR-код в qr",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.QRRequest"
+                        }
+                    ]
                 },
-                "pickup_token": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreatePassRequest": {
+        "dto.PickupResponse": {
             "type": "object",
             "properties": {
-                "attrs": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "issuer_key_id": {
+                    "type": "string"
                 },
-                "exp": {
+                "payload": {
                     "type": "string"
                 },
-                "nbf": {
+                "qr": {
+                    "description": "QR — data URI изображения, если запрошен qr",
                     "type": "string"
                 },
-                "one_time": {
+                "qr_error": {
+                    "description": "QRError — код ошибки, если QR-код построить не удалось (qr_too_large или другой); payload при этом выдан",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PolicyResponse": {
+            "type": "object",
+            "properties": {
+                "allowed_zones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attrs_schema": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "default_one_time": {
                     "type": "boolean"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "issuers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level": {
+                    "type": "string"
+                },
+                "max_ttl_s": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
                 "org_id": {
                     "type": "string"
                 },
-                "policy_id": {
+                "required_attrs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.QRRequest": {
+            "type": "object",
+            "properties": {
+                "ecc": {
                     "type": "string"
                 },
-                "subject_name": {
+                "format": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RedeemRequest": {
+            "type": "object",
+            "properties": {
+                "reader_id": {
                     "type": "string"
                 },
                 "zone_id": {
@@ -318,9 +2555,37 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.CreatePassResponse": {
+        "dto.RedeemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "redeemed_at": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RenewRequest": {
+            "type": "object",
+            "properties": {
+                "exp": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RenewResponse": {
             "type": "object",
             "properties": {
+                "exp": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -330,68 +2595,175 @@ const docTemplate = `{
                 "payload": {
                     "type": "string"
                 },
-                "status": {
+                "replaces_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.JWK": {
+        "dto.RevocationListResponse": {
             "type": "object",
             "properties": {
-                "alg": {
+                "count": {
+                    "type": "integer"
+                },
+                "issuer_key_id": {
                     "type": "string"
                 },
-                "crv": {
+                "payload": {
                     "type": "string"
                 },
-                "kid": {
+                "since": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RevokeRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
                     "type": "string"
                 },
-                "kty": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RevokeResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "x": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "dto.JWKSet": {
+        "dto.StatusListResponse": {
             "type": "object",
             "properties": {
-                "keys": {
+                "issuer_key_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdatePolicyRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_zones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attrs_schema": {
+                    "type": "object"
+                },
+                "default_one_time": {
+                    "type": "boolean"
+                },
+                "issuers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level": {
+                    "type": "string"
+                },
+                "max_ttl_s": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required_attrs": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.JWK"
+                        "type": "string"
                     }
                 }
             }
         },
-        "dto.PickupRequest": {
+        "dto.UpdateZoneRequest": {
             "type": "object",
             "properties": {
-                "token": {
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.PickupResponse": {
+        "dto.VerifyRequest": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VerifyResponse": {
             "type": "object",
             "properties": {
+                "exp": {
+                    "type": "string"
+                },
                 "issuer_key_id": {
                     "type": "string"
                 },
-                "payload": {
+                "nbf": {
+                    "type": "string"
+                },
+                "pass_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
                     "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.RevokeResponse": {
+        "dto.ZoneResponse": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "status": {
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "parent_id": {
                     "type": "string"
                 }
             }
@@ -423,6 +2795,63 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Schedule": {
+            "type": "object",
+            "properties": {
+                "exceptions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleWindow"
+                    }
+                }
+            }
+        },
+        "models.ScheduleWindow": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -12,6 +12,13 @@ type Config struct {
 	DatabaseURL   string
 	MaxTTL        time.Duration
 	EnableSwagger bool
+	SweepInterval time.Duration
+	ClockSkew     time.Duration
+	StatusListURL string
+	// IdempotencyTTL — сколько хранится ответ на запрос с Idempotency-Key
+	IdempotencyTTL time.Duration
+	// StrictPolicies — отклонять policy_id, не зарегистрированные за организацией
+	StrictPolicies bool
 }
 
 func getenv(key, def string) string {
@@ -29,17 +36,41 @@ func Load() Config {
 	if err != nil || ttlHours <= 0 {
 		ttlHours = 24
 	}
+	sweepSecStr := getenv("SWEEP_INTERVAL_S", "60")
+	sweepSec, err := strconv.Atoi(sweepSecStr)
+	if err != nil || sweepSec <= 0 {
+		sweepSec = 60
+	}
+	skewSecStr := getenv("VERIFY_SKEW_S", "60")
+	skewSec, err := strconv.Atoi(skewSecStr)
+	if err != nil || skewSec < 0 {
+		skewSec = 60
+	}
+	idemHoursStr := getenv("IDEMPOTENCY_TTL_H", "24")
+	idemHours, err := strconv.Atoi(idemHoursStr)
+	if err != nil || idemHours <= 0 {
+		idemHours = 24
+	}
+	strict, err := strconv.ParseBool(getenv("STRICT_POLICIES", "false"))
+	if err != nil {
+		strict = false
+	}
 	swagEnv := getenv("ENABLE_SWAGGER", "false")
 	b, err := strconv.ParseBool(swagEnv)
 	if err != nil {
 		b = false
 	}
 	cfg := Config{
-		Bind:          bind,
-		DatabaseURL:   db,
-		MaxTTL:        time.Duration(ttlHours) * time.Hour,
-		EnableSwagger: b,
+		Bind:           bind,
+		DatabaseURL:    db,
+		MaxTTL:         time.Duration(ttlHours) * time.Hour,
+		EnableSwagger:  b,
+		SweepInterval:  time.Duration(sweepSec) * time.Second,
+		ClockSkew:      time.Duration(skewSec) * time.Second,
+		StatusListURL:  getenv("STATUS_LIST_URL", "http://localhost:8081/api/v1/status-list"),
+		IdempotencyTTL: time.Duration(idemHours) * time.Hour,
+		StrictPolicies: strict,
 	}
-	log.Printf("config: bind=%s ttl=%s swagger=%v", cfg.Bind, cfg.MaxTTL, cfg.EnableSwagger)
+	log.Printf("config: bind=%s ttl=%s swagger=%v sweep=%s skew=%s idempotency=%s strict_policies=%v", cfg.Bind, cfg.MaxTTL, cfg.EnableSwagger, cfg.SweepInterval, cfg.ClockSkew, cfg.IdempotencyTTL, cfg.StrictPolicies)
 	return cfg
 }
@@ -4,6 +4,13 @@ import (
 	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMalformedJWS = errors.New("malformed jws")
+	ErrBadSignature = errors.New("bad signature")
 )
 
 type JWSHeader struct {
@@ -26,3 +33,39 @@ func SignJWS(kid string, priv ed25519.PrivateKey, payload []byte) (compact strin
 	sEnc := base64.RawURLEncoding.EncodeToString(sig)
 	return signingInput + "." + sEnc, sig, nil
 }
+
+// ParseJWS разбирает compact JWS без проверки подписи
+func ParseJWS(compact string) (hdr JWSHeader, payload []byte, err error) {
+	parts := strings.Split(compact, ".")
+	if len(parts) != 3 {
+		return JWSHeader{}, nil, ErrMalformedJWS
+	}
+	hdrB, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return JWSHeader{}, nil, ErrMalformedJWS
+	}
+	if err := json.Unmarshal(hdrB, &hdr); err != nil {
+		return JWSHeader{}, nil, ErrMalformedJWS
+	}
+	payload, err = base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return JWSHeader{}, nil, ErrMalformedJWS
+	}
+	return hdr, payload, nil
+}
+
+// VerifyJWS проверяет подпись EdDSA compact JWS
+func VerifyJWS(compact string, pub ed25519.PublicKey) error {
+	i := strings.LastIndexByte(compact, '.')
+	if i < 0 || len(pub) != ed25519.PublicKeySize {
+		return ErrMalformedJWS
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(compact[i+1:])
+	if err != nil {
+		return ErrMalformedJWS
+	}
+	if !ed25519.Verify(pub, []byte(compact[:i]), sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
@@ -1,15 +1,24 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+)
 
 type CreatePassRequest struct {
 	OrgID       string         `json:"org_id"`
 	PolicyID    string         `json:"policy_id"`
 	SubjectName string         `json:"subject_name"`
 	ZoneID      string         `json:"zone_id"`
+	ZoneIDs     []string       `json:"zone_ids,omitempty"`
+	ExpandZones bool           `json:"expand_zones,omitempty"`
 	NBF         time.Time      `json:"nbf"`
 	EXP         time.Time      `json:"exp"`
-	OneTime     bool           `json:"one_time"`
+	OneTime     *bool          `json:"one_time,omitempty"`
+	MaxUses     int            `json:"max_uses,omitempty"`
+	Schedule    *im.Schedule   `json:"schedule,omitempty"`
 	Attrs       map[string]any `json:"attrs"`
 }
 
@@ -20,9 +29,155 @@ type CreatePassResponse struct {
 	Payload     string `json:"payload"`
 }
 
+// BatchCreatePassesRequest — пакетный выпуск; all_or_nothing отменяет весь пакет при ошибке любого элемента
+type BatchCreatePassesRequest struct {
+	Items        []CreatePassRequest `json:"items"`
+	AllOrNothing bool                `json:"all_or_nothing"`
+}
+
+type BatchCreatePassesResponse struct {
+	Issued  int               `json:"issued"`
+	Failed  int               `json:"failed"`
+	Skipped int               `json:"skipped"`
+	Items   []BatchItemResult `json:"items"`
+}
+
+// BatchItemResult — итог элемента пакета по его индексу: status issued, failed или skipped
+// (не выпущен из-за all_or_nothing); при ошибке заполнен error
+type BatchItemResult struct {
+	Index       int             `json:"index"`
+	Status      string          `json:"status"`
+	ID          string          `json:"id,omitempty"`
+	IssuerKeyID string          `json:"issuer_key_id,omitempty"`
+	Payload     string          `json:"payload,omitempty"`
+	Error       *BatchItemError `json:"error,omitempty"`
+}
+
+// BatchItemError — ошибка элемента в том же виде, что и тело ответа об ошибке
+type BatchItemError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+type PassResponse struct {
+	ID               string       `json:"id"`
+	OrgID            string       `json:"org_id"`
+	PolicyID         string       `json:"policy_id"`
+	SubjectName      string       `json:"subject_name"`
+	ZoneID           string       `json:"zone_id"`
+	ZoneIDs          []string     `json:"zone_ids"`
+	NBF              time.Time    `json:"nbf"`
+	EXP              time.Time    `json:"exp"`
+	OneTime          bool         `json:"one_time"`
+	MaxUses          int          `json:"max_uses,omitempty"`
+	Schedule         *im.Schedule `json:"schedule,omitempty"`
+	Uses             int          `json:"uses"`
+	Version          int          `json:"version"`
+	IssuerKeyID      string       `json:"issuer_key_id"`
+	Status           string       `json:"status"`
+	CreatedAt        time.Time    `json:"created_at"`
+	RevokedAt        *time.Time   `json:"revoked_at,omitempty"`
+	RevocationReason string       `json:"revocation_reason,omitempty"`
+	RevocationNote   string       `json:"revocation_note,omitempty"`
+	RevokedBy        string       `json:"revoked_by,omitempty"`
+	SuspendedAt      *time.Time   `json:"suspended_at,omitempty"`
+	SuspendedBy      string       `json:"suspended_by,omitempty"`
+	ReplacesID       string       `json:"replaces_id,omitempty"`
+	Payload          string       `json:"payload"`
+}
+
+type ListPassesResponse struct {
+	Items      []PassResponse `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type RevokeRequest struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
 type RevokeResponse struct {
 	ID     string `json:"id"`
 	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// AmendPassRequest — частичное изменение; отсутствующее поле не меняется, attrs и набор зон
+// заменяются целиком
+type AmendPassRequest struct {
+	ZoneID      *string         `json:"zone_id,omitempty"`
+	ZoneIDs     *[]string       `json:"zone_ids,omitempty"`
+	ExpandZones bool            `json:"expand_zones,omitempty"`
+	SubjectName *string         `json:"subject_name,omitempty"`
+	Attrs       *map[string]any `json:"attrs,omitempty"`
+}
+
+type AmendPassResponse struct {
+	ID          string `json:"id"`
+	Version     int    `json:"version"`
+	IssuerKeyID string `json:"issuer_key_id"`
+	Payload     string `json:"payload"`
+}
+
+type RenewRequest struct {
+	EXP time.Time `json:"exp"`
+}
+
+type RenewResponse struct {
+	ID          string    `json:"id"`
+	ReplacesID  string    `json:"replaces_id"`
+	IssuerKeyID string    `json:"issuer_key_id"`
+	EXP         time.Time `json:"exp"`
+	Payload     string    `json:"payload"`
+}
+
+// BulkRevokeRequest — массовый отзыв; нужен org_id и хотя бы одно условие помимо него
+type BulkRevokeRequest struct {
+	OrgID       string `json:"org_id"`
+	SubjectName string `json:"subject_name"`
+	ZoneID      string `json:"zone_id"`
+	PolicyID    string `json:"policy_id"`
+	IssuerKeyID string `json:"issuer_key_id"`
+	DryRun      bool   `json:"dry_run"`
+	Reason      string `json:"reason"`
+	Note        string `json:"note"`
+}
+
+type BulkRevokeResponse struct {
+	DryRun  bool     `json:"dry_run"`
+	Matched int      `json:"matched"`
+	Revoked int      `json:"revoked"`
+	IDs     []string `json:"ids"`
+}
+
+type CompromiseKeyRequest struct {
+	Reissue bool   `json:"reissue"`
+	Note    string `json:"note"`
+}
+
+// CompromisedPassResponse — строка отчёта; replacement_* заполнены, если пропуск перевыпущен
+type CompromisedPassResponse struct {
+	ID                 string    `json:"id"`
+	OrgID              string    `json:"org_id"`
+	SubjectName        string    `json:"subject_name"`
+	PreviousStatus     string    `json:"previous_status"`
+	EXP                time.Time `json:"exp"`
+	ReplacementID      string    `json:"replacement_id,omitempty"`
+	ReplacementPayload string    `json:"replacement_payload,omitempty"`
+}
+
+type CompromiseKeyResponse struct {
+	KeyID    string                    `json:"key_id"`
+	NewKeyID string                    `json:"new_key_id,omitempty"`
+	Revoked  int                       `json:"revoked"`
+	Reissued int                       `json:"reissued"`
+	Passes   []CompromisedPassResponse `json:"passes"`
+}
+
+type PassStatusResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
 }
 
 type ApproveResponse struct {
@@ -31,11 +186,133 @@ type ApproveResponse struct {
 	ExpiresAt   string `json:"expires_at"`
 }
 
+type RedeemRequest struct {
+	ReaderID string `json:"reader_id"`
+	ZoneID   string `json:"zone_id"`
+}
+
+type RedeemResponse struct {
+	ID         string    `json:"id"`
+	Uses       int       `json:"uses"`
+	Remaining  *int      `json:"remaining,omitempty"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
 type PickupRequest struct {
 	Token string `json:"token"`
+	// QR — вернуть вместе с payload QR-код в qr
+	QR *QRRequest `json:"qr,omitempty"`
+}
+
+// QRRequest — параметры QR-кода: format png|svg, size — сторона в пикселях, ecc — L|M|Q|H
+type QRRequest struct {
+	Format string `json:"format"`
+	Size   int    `json:"size"`
+	ECC    string `json:"ecc"`
 }
 
 type PickupResponse struct {
 	Payload     string `json:"payload"`
 	IssuerKeyID string `json:"issuer_key_id"`
+	// QR — data URI изображения, если запрошен qr
+	QR string `json:"qr,omitempty"`
+	// QRError — код ошибки, если QR-код построить не удалось (qr_too_large или другой); payload при этом выдан
+	QRError string `json:"qr_error,omitempty"`
+}
+
+type VerifyRequest struct {
+	Payload string `json:"payload"`
+}
+
+type VerifyResponse struct {
+	Valid       bool       `json:"valid"`
+	Reason      string     `json:"reason"`
+	PassID      string     `json:"pass_id,omitempty"`
+	IssuerKeyID string     `json:"issuer_key_id,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Scopes      []string   `json:"scopes,omitempty"`
+	NBF         *time.Time `json:"nbf,omitempty"`
+	EXP         *time.Time `json:"exp,omitempty"`
+}
+
+type RevocationListResponse struct {
+	Version     int64  `json:"version"`
+	Since       int64  `json:"since"`
+	Count       int    `json:"count"`
+	IssuerKeyID string `json:"issuer_key_id"`
+	Payload     string `json:"payload"`
+}
+
+type StatusListResponse struct {
+	IssuerKeyID string `json:"issuer_key_id"`
+	Payload     string `json:"payload"`
+}
+
+type CreateZoneRequest struct {
+	ID       string `json:"id"`
+	OrgID    string `json:"org_id"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// UpdateZoneRequest — отсутствующее поле не меняется; parent_id="" недопустим для этажа и комнаты
+type UpdateZoneRequest struct {
+	Name     *string `json:"name,omitempty"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+type ZoneResponse struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListZonesResponse struct {
+	Items []ZoneResponse `json:"items"`
+}
+
+// PolicySpec — изменяемая часть политики; пустые allowed_zones и issuers не ограничивают,
+// max_ttl_s=0 — действует глобальный MAX_TTL_H, attrs_schema — JSON Schema объекта attrs
+type PolicySpec struct {
+	Name           string          `json:"name"`
+	Level          string          `json:"level,omitempty"`
+	AllowedZones   []string        `json:"allowed_zones"`
+	MaxTTLSeconds  int64           `json:"max_ttl_s,omitempty"`
+	DefaultOneTime bool            `json:"default_one_time"`
+	RequiredAttrs  []string        `json:"required_attrs"`
+	AttrsSchema    json.RawMessage `json:"attrs_schema,omitempty" swaggertype:"object"`
+	Issuers        []string        `json:"issuers"`
+}
+
+type CreatePolicyRequest struct {
+	ID    string `json:"id"`
+	OrgID string `json:"org_id"`
+	PolicySpec
+}
+
+// UpdatePolicyRequest — полная замена правил; id и организация неизменны
+type UpdatePolicyRequest struct {
+	PolicySpec
+}
+
+type PolicyResponse struct {
+	ID    string `json:"id"`
+	OrgID string `json:"org_id"`
+	PolicySpec
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListPoliciesResponse struct {
+	Items []PolicyResponse `json:"items"`
+}
+
+// ViolationDetail — нарушенное правило политики в details ответа 422
+type ViolationDetail struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
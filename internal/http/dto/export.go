@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+	"github.com/vbncursed/vkr/issue-service/internal/util"
+)
+
+var (
+	ErrOrgRequired   = errors.New("org_id required")
+	ErrInvalidFormat = errors.New("invalid export format")
+)
+
+// Форматы выгрузки пропусков
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+// ParseExportQuery читает фильтры выгрузки (как у списка, org_id обязателен) и формат
+// csv (по умолчанию) или ndjson
+func ParseExportQuery(q url.Values) (f issvc.PassFilter, format string, err error) {
+	f, err = ParsePassFilter(q)
+	if err != nil {
+		return issvc.PassFilter{}, "", err
+	}
+	if f.OrgID == "" {
+		return issvc.PassFilter{}, "", ErrOrgRequired
+	}
+	format = strings.ToLower(strings.TrimSpace(q.Get("format")))
+	switch format {
+	case "":
+		format = ExportCSV
+	case ExportCSV, ExportNDJSON:
+	default:
+		return issvc.PassFilter{}, "", ErrInvalidFormat
+	}
+	return f, format, nil
+}
+
+// ExportCSVHeader — колонки CSV-выгрузки; JWS в CSV не входит, он есть в ndjson
+var ExportCSVHeader = []string{
+	"id", "org_id", "policy_id", "subject_name", "zone_ids", "nbf", "exp", "one_time", "max_uses", "uses",
+	"version", "status", "issuer_key_id", "created_at", "revoked_at", "revocation_reason", "revocation_note",
+	"revoked_by", "suspended_at", "suspended_by", "replaces_id",
+}
+
+// ExportCSVRecord — строка CSV-выгрузки в порядке ExportCSVHeader; время — RFC3339 UTC,
+// зоны — через пробел, пустой max_uses — без лимита. Строки, которые задаёт клиент, включая
+// id политики, зон и ключа, экранируются от формул (util.SpreadsheetSafe).
+func ExportCSVRecord(v issvc.PassView) []string {
+	maxUses := ""
+	if v.MaxUses > 0 {
+		maxUses = strconv.Itoa(v.MaxUses)
+	}
+	safe := util.SpreadsheetSafe
+	return []string{
+		v.ID, v.OrgID, safe(v.PolicyID), safe(v.SubjectName), safe(strings.Join(v.ZoneIDs, " ")),
+		csvTime(&v.NBF), csvTime(&v.EXP), strconv.FormatBool(v.OneTime), maxUses, strconv.Itoa(v.Uses),
+		strconv.Itoa(v.Version), v.Status, safe(v.IssuerKeyID), csvTime(&v.CreatedAt), csvTime(v.RevokedAt), safe(v.RevocationReason),
+		safe(v.RevocationNote), safe(v.RevokedBy), csvTime(v.SuspendedAt),
+		safe(v.SuspendedBy), v.ReplacesID,
+	}
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
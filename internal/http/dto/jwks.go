@@ -3,19 +3,14 @@ package dto
 import (
 	"encoding/base64"
 
+	"github.com/vbncursed/vkr/issue-service/internal/models"
 	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
 )
 
-type JWK struct {
-	Kty string `json:"kty"`
-	Crv string `json:"crv"`
-	Kid string `json:"kid"`
-	Alg string `json:"alg"`
-	X   string `json:"x"`
-}
-type JWKSet struct {
-	Keys []JWK `json:"keys"`
-}
+type (
+	JWK    = models.JWK
+	JWKSet = models.JWKSet
+)
 
 // FromIssuerKeys маппит доменные ключи в JWKSet
 func FromIssuerKeys(keys []issvc.IssuerKey) JWKSet {
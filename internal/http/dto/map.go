@@ -1,22 +1,65 @@
 package dto
 
 import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
 	im "github.com/vbncursed/vkr/issue-service/internal/models"
 	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
 )
 
-// ToCommand преобразует CreatePassRequest в команду use case
-func (r CreatePassRequest) ToCommand() issvc.IssuePassCommand {
+// ToCommand преобразует CreatePassRequest в команду use case; actor сверяется с issuers политики
+func (r CreatePassRequest) ToCommand(actor string) issvc.IssuePassCommand {
 	return issvc.IssuePassCommand{
 		OrgID:       r.OrgID,
 		PolicyID:    r.PolicyID,
 		SubjectName: r.SubjectName,
-		ZoneID:      r.ZoneID,
+		ZoneIDs:     zoneList(r.ZoneID, r.ZoneIDs),
+		ExpandZones: r.ExpandZones,
 		NBF:         r.NBF,
 		EXP:         r.EXP,
 		OneTime:     r.OneTime,
+		MaxUses:     r.MaxUses,
+		Schedule:    r.Schedule,
 		Attrs:       r.Attrs,
+		Actor:       actor,
+	}
+}
+
+// zoneList — zone_id и zone_ids одним списком без пустых значений и повторов; zone_id идёт первым
+func zoneList(zoneID string, zoneIDs []string) []string {
+	return trimList(append([]string{zoneID}, zoneIDs...))
+}
+
+// trimList — значения без пробелов по краям, пустых и повторов, в исходном порядке
+func trimList(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, v := range in {
+		v = strings.TrimSpace(v)
+		if v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
 	}
+	return out
+}
+
+// zones — новый набор зон, если в запросе есть zone_id или zone_ids
+func (r AmendPassRequest) zones() ([]string, bool) {
+	if r.ZoneID == nil && r.ZoneIDs == nil {
+		return nil, false
+	}
+	var zoneID string
+	var zoneIDs []string
+	if r.ZoneID != nil {
+		zoneID = *r.ZoneID
+	}
+	if r.ZoneIDs != nil {
+		zoneIDs = *r.ZoneIDs
+	}
+	return zoneList(zoneID, zoneIDs), true
 }
 
 // FromIssueResult формирует ответ по результату use case
@@ -29,9 +72,157 @@ func FromIssueResult(res issvc.IssuePassResult) CreatePassResponse {
 	}
 }
 
+// FromPassView формирует карточку пропуска
+func FromPassView(v issvc.PassView) PassResponse {
+	return PassResponse{
+		ID:               v.ID,
+		OrgID:            v.OrgID,
+		PolicyID:         v.PolicyID,
+		SubjectName:      v.SubjectName,
+		ZoneID:           v.ZoneID,
+		ZoneIDs:          v.ZoneIDs,
+		NBF:              v.NBF.UTC(),
+		EXP:              v.EXP.UTC(),
+		OneTime:          v.OneTime,
+		MaxUses:          v.MaxUses,
+		Schedule:         v.Schedule,
+		Uses:             v.Uses,
+		Version:          v.Version,
+		IssuerKeyID:      v.IssuerKeyID,
+		Status:           v.Status,
+		CreatedAt:        v.CreatedAt.UTC(),
+		RevokedAt:        v.RevokedAt,
+		RevocationReason: v.RevocationReason,
+		RevocationNote:   v.RevocationNote,
+		RevokedBy:        v.RevokedBy,
+		SuspendedAt:      v.SuspendedAt,
+		SuspendedBy:      v.SuspendedBy,
+		ReplacesID:       v.ReplacesID,
+		Payload:          v.Payload,
+	}
+}
+
+// FromListResult формирует страницу списка
+func FromListResult(r issvc.ListPassesResult) ListPassesResponse {
+	out := ListPassesResponse{Items: make([]PassResponse, 0, len(r.Items)), NextCursor: r.NextCursor}
+	for _, v := range r.Items {
+		out.Items = append(out.Items, FromPassView(v))
+	}
+	return out
+}
+
+// ToCommand преобразует RevokeRequest в команду use case
+func (r RevokeRequest) ToCommand(id, actor string) issvc.RevokePassCommand {
+	reason := im.RevocationReason(r.Reason)
+	if reason == "" {
+		reason = im.RevocationOther
+	}
+	return issvc.RevokePassCommand{ID: id, Reason: reason, Note: strings.TrimSpace(r.Note), Actor: actor}
+}
+
 // Revoke
-func RevokeResponseOK(id string) RevokeResponse {
-	return RevokeResponse{ID: id, Status: string(im.StatusRevoked)}
+func RevokeResponseOK(id string, reason im.RevocationReason) RevokeResponse {
+	return RevokeResponse{ID: id, Status: string(im.StatusRevoked), Reason: string(reason)}
+}
+
+// ToCommand преобразует AmendPassRequest в команду use case
+func (r AmendPassRequest) ToCommand(id, actor string) issvc.AmendPassCommand {
+	cmd := issvc.AmendPassCommand{ID: id, ExpandZones: r.ExpandZones, Actor: actor}
+	if zones, ok := r.zones(); ok {
+		cmd.ZoneIDs = zones
+	}
+	if r.SubjectName != nil {
+		name := strings.TrimSpace(*r.SubjectName)
+		cmd.SubjectName = &name
+	}
+	if r.Attrs != nil {
+		cmd.Attrs = *r.Attrs
+		if cmd.Attrs == nil {
+			cmd.Attrs = map[string]any{}
+		}
+	}
+	return cmd
+}
+
+// FromAmendResult — ответ на изменение пропуска
+func FromAmendResult(r issvc.AmendPassResult) AmendPassResponse {
+	return AmendPassResponse{ID: r.ID, Version: r.Version, IssuerKeyID: r.IssuerKeyID, Payload: r.Payload}
+}
+
+// ToCommand преобразует RenewRequest в команду use case
+func (r RenewRequest) ToCommand(id, actor string) issvc.RenewPassCommand {
+	return issvc.RenewPassCommand{ID: id, EXP: r.EXP.UTC(), Actor: actor}
+}
+
+// FromRenewResult — ответ продления
+func FromRenewResult(r issvc.RenewPassResult) RenewResponse {
+	return RenewResponse{ID: r.ID, ReplacesID: r.ReplacesID, IssuerKeyID: r.IssuerKeyID, EXP: r.EXP.UTC(), Payload: r.Payload}
+}
+
+// ToCommand преобразует BulkRevokeRequest в команду use case
+func (r BulkRevokeRequest) ToCommand(actor string) issvc.BulkRevokeCommand {
+	reason := im.RevocationReason(r.Reason)
+	if reason == "" {
+		reason = im.RevocationOther
+	}
+	return issvc.BulkRevokeCommand{
+		Filter: issvc.BulkRevokeFilter{
+			OrgID:       strings.TrimSpace(r.OrgID),
+			SubjectName: strings.TrimSpace(r.SubjectName),
+			ZoneID:      strings.TrimSpace(r.ZoneID),
+			PolicyID:    strings.TrimSpace(r.PolicyID),
+			IssuerKeyID: strings.TrimSpace(r.IssuerKeyID),
+		},
+		DryRun: r.DryRun,
+		Reason: reason,
+		Note:   strings.TrimSpace(r.Note),
+		Actor:  actor,
+	}
+}
+
+// FromBulkRevokeResult — ответ массового отзыва; ids всегда массив
+func FromBulkRevokeResult(r issvc.BulkRevokeResult) BulkRevokeResponse {
+	ids := r.IDs
+	if ids == nil {
+		ids = []string{}
+	}
+	return BulkRevokeResponse{DryRun: r.DryRun, Matched: r.Matched, Revoked: r.Revoked, IDs: ids}
+}
+
+// ToCommand преобразует CompromiseKeyRequest в команду use case
+func (r CompromiseKeyRequest) ToCommand(kid, actor string) issvc.CompromiseKeyCommand {
+	return issvc.CompromiseKeyCommand{KID: kid, Reissue: r.Reissue, Note: strings.TrimSpace(r.Note), Actor: actor}
+}
+
+// FromCompromiseKeyReport — отчёт о затронутых пропусках
+func FromCompromiseKeyReport(r issvc.CompromiseKeyReport) CompromiseKeyResponse {
+	out := CompromiseKeyResponse{
+		KeyID:    r.KeyID,
+		NewKeyID: r.NewKeyID,
+		Revoked:  r.Revoked,
+		Reissued: r.Reissued,
+		Passes:   make([]CompromisedPassResponse, 0, len(r.Passes)),
+	}
+	for _, p := range r.Passes {
+		row := CompromisedPassResponse{
+			ID:             p.View.ID,
+			OrgID:          p.View.OrgID,
+			SubjectName:    p.View.SubjectName,
+			PreviousStatus: p.View.Status,
+			EXP:            p.View.EXP.UTC(),
+		}
+		if p.Replacement != nil {
+			row.ReplacementID = p.Replacement.ID
+			row.ReplacementPayload = string(p.Replacement.Payload)
+		}
+		out.Passes = append(out.Passes, row)
+	}
+	return out
+}
+
+// PassStatusOK — ответ на смену статуса
+func PassStatusOK(id string, st im.PassStatus) PassStatusResponse {
+	return PassStatusResponse{ID: id, Status: string(st)}
 }
 
 // Approve
@@ -39,7 +230,175 @@ func FromApproveResult(id string, r issvc.ApproveResult) ApproveResponse {
 	return ApproveResponse{ID: id, PickupToken: r.Token, ExpiresAt: r.ExpiresAt}
 }
 
+// ToCommand преобразует RedeemRequest в команду use case
+func (r RedeemRequest) ToCommand(id string) issvc.RedeemCommand {
+	return issvc.RedeemCommand{
+		PassID:   id,
+		ReaderID: strings.TrimSpace(r.ReaderID),
+		ZoneID:   strings.TrimSpace(r.ZoneID),
+	}
+}
+
+// Redeem
+func FromRedeemResult(r issvc.RedeemResult) RedeemResponse {
+	return RedeemResponse{ID: r.PassID, Uses: r.Uses, Remaining: r.Remaining, RedeemedAt: r.RedeemedAt}
+}
+
 // Pickup
 func FromPickupResult(r issvc.PickupResult) PickupResponse {
 	return PickupResponse{Payload: r.Payload, IssuerKeyID: r.IssuerKeyID}
 }
+
+// FromVerifyResult формирует вердикт проверки
+func FromVerifyResult(r issvc.VerifyResult) VerifyResponse {
+	out := VerifyResponse{
+		Valid:       r.Valid,
+		Reason:      string(r.Reason),
+		PassID:      r.PassID,
+		IssuerKeyID: r.IssuerKeyID,
+		Status:      r.Status,
+		Scopes:      r.Scopes,
+	}
+	if !r.NBF.IsZero() {
+		nbf := r.NBF.UTC()
+		out.NBF = &nbf
+	}
+	if !r.EXP.IsZero() {
+		exp := r.EXP.UTC()
+		out.EXP = &exp
+	}
+	return out
+}
+
+// FromRevocationList формирует ответ со списком отзывов
+func FromRevocationList(r issvc.RevocationListResult) RevocationListResponse {
+	return RevocationListResponse{
+		Version:     r.Version,
+		Since:       r.Since,
+		Count:       r.Count,
+		IssuerKeyID: r.IssuerKeyID,
+		Payload:     r.Payload,
+	}
+}
+
+// FromStatusList формирует ответ со списком статусов
+func FromStatusList(r issvc.StatusListResult) StatusListResponse {
+	return StatusListResponse{IssuerKeyID: r.IssuerKeyID, Payload: r.Payload}
+}
+
+// ToZone преобразует CreateZoneRequest в доменную зону
+func (r CreateZoneRequest) ToZone() issvc.Zone {
+	return issvc.Zone{
+		ID:       strings.TrimSpace(r.ID),
+		OrgID:    strings.TrimSpace(r.OrgID),
+		Name:     strings.TrimSpace(r.Name),
+		Kind:     im.ZoneKind(r.Kind),
+		ParentID: strings.TrimSpace(r.ParentID),
+	}
+}
+
+// ToCommand преобразует UpdateZoneRequest в команду use case
+func (r UpdateZoneRequest) ToCommand(orgID, id string) issvc.UpdateZoneCommand {
+	cmd := issvc.UpdateZoneCommand{OrgID: orgID, ID: id}
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		cmd.Name = &name
+	}
+	if r.ParentID != nil {
+		parent := strings.TrimSpace(*r.ParentID)
+		cmd.ParentID = &parent
+	}
+	return cmd
+}
+
+// FromZone формирует карточку зоны
+func FromZone(z issvc.Zone) ZoneResponse {
+	return ZoneResponse{ID: z.ID, OrgID: z.OrgID, Name: z.Name, Kind: string(z.Kind), ParentID: z.ParentID, CreatedAt: z.CreatedAt.UTC()}
+}
+
+// FromZones формирует список зон
+func FromZones(zs []issvc.Zone) ListZonesResponse {
+	out := ListZonesResponse{Items: make([]ZoneResponse, 0, len(zs))}
+	for _, z := range zs {
+		out.Items = append(out.Items, FromZone(z))
+	}
+	return out
+}
+
+// toPolicy — доменная политика из изменяемой части запроса
+func (r PolicySpec) toPolicy(id, orgID string) issvc.Policy {
+	return issvc.Policy{
+		ID:             id,
+		OrgID:          orgID,
+		Name:           strings.TrimSpace(r.Name),
+		Level:          strings.TrimSpace(r.Level),
+		AllowedZones:   trimList(r.AllowedZones),
+		MaxTTL:         time.Duration(r.MaxTTLSeconds) * time.Second,
+		DefaultOneTime: r.DefaultOneTime,
+		RequiredAttrs:  trimList(r.RequiredAttrs),
+		AttrsSchema:    r.attrsSchema(),
+		Issuers:        trimList(r.Issuers),
+	}
+}
+
+// attrsSchema — схема attrs; null равносилен отсутствию
+func (r PolicySpec) attrsSchema() json.RawMessage {
+	if string(bytes.TrimSpace(r.AttrsSchema)) == "null" {
+		return nil
+	}
+	return r.AttrsSchema
+}
+
+// ToPolicy преобразует CreatePolicyRequest в доменную политику
+func (r CreatePolicyRequest) ToPolicy() issvc.Policy {
+	return r.PolicySpec.toPolicy(strings.TrimSpace(r.ID), strings.TrimSpace(r.OrgID))
+}
+
+// ToPolicy преобразует UpdatePolicyRequest в политику организации с данным id
+func (r UpdatePolicyRequest) ToPolicy(orgID, id string) issvc.Policy {
+	return r.PolicySpec.toPolicy(id, orgID)
+}
+
+// FromPolicy формирует карточку политики; списки всегда массивы
+func FromPolicy(p issvc.Policy) PolicyResponse {
+	orEmpty := func(v []string) []string {
+		if v == nil {
+			return []string{}
+		}
+		return v
+	}
+	return PolicyResponse{
+		ID:    p.ID,
+		OrgID: p.OrgID,
+		PolicySpec: PolicySpec{
+			Name:           p.Name,
+			Level:          p.Level,
+			AllowedZones:   orEmpty(p.AllowedZones),
+			MaxTTLSeconds:  int64(p.MaxTTL / time.Second),
+			DefaultOneTime: p.DefaultOneTime,
+			RequiredAttrs:  orEmpty(p.RequiredAttrs),
+			AttrsSchema:    p.AttrsSchema,
+			Issuers:        orEmpty(p.Issuers),
+		},
+		CreatedAt: p.CreatedAt.UTC(),
+		UpdatedAt: p.UpdatedAt.UTC(),
+	}
+}
+
+// FromPolicies формирует список политик
+func FromPolicies(ps []issvc.Policy) ListPoliciesResponse {
+	out := ListPoliciesResponse{Items: make([]PolicyResponse, 0, len(ps))}
+	for _, p := range ps {
+		out.Items = append(out.Items, FromPolicy(p))
+	}
+	return out
+}
+
+// FromViolations — нарушения политики для details ответа
+func FromViolations(vs []issvc.Violation) []ViolationDetail {
+	out := make([]ViolationDetail, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, ViolationDetail{Field: v.Field, Message: v.Message})
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+package dto
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vbncursed/vkr/issue-service/internal/importer"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+var (
+	ErrInvalidOrgID  = errors.New("invalid org_id")
+	ErrInvalidStatus = errors.New("invalid status")
+	ErrInvalidTime   = errors.New("invalid time filter")
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidSince  = errors.New("invalid since")
+	ErrInvalidTZ     = errors.New("invalid tz")
+	ErrInvalidPickup = errors.New("invalid pickup")
+	// ErrInvalidPickupTTL — pickup_ttl_h не целое от 1 до MaxPickupTTL в часах
+	ErrInvalidPickupTTL = errors.New("invalid pickup_ttl_h")
+)
+
+// ParsePassFilter читает фильтры списка пропусков из query-параметров
+func ParsePassFilter(q url.Values) (issvc.PassFilter, error) {
+	f := issvc.PassFilter{
+		OrgID:         strings.TrimSpace(q.Get("org_id")),
+		PolicyID:      strings.TrimSpace(q.Get("policy_id")),
+		ZoneID:        strings.TrimSpace(q.Get("zone_id")),
+		Status:        strings.TrimSpace(q.Get("status")),
+		SubjectPrefix: q.Get("subject_name"),
+	}
+	if f.OrgID != "" {
+		if _, err := uuid.Parse(f.OrgID); err != nil {
+			return issvc.PassFilter{}, ErrInvalidOrgID
+		}
+	}
+	if f.Status != "" {
+		switch im.PassStatus(f.Status) {
+		case im.StatusActive, im.StatusSuspended, im.StatusRevoked, im.StatusExpired:
+		default:
+			return issvc.PassFilter{}, ErrInvalidStatus
+		}
+	}
+	for _, p := range []struct {
+		key string
+		dst **time.Time
+	}{
+		{"nbf_from", &f.NBFFrom},
+		{"nbf_to", &f.NBFTo},
+		{"exp_from", &f.EXPFrom},
+		{"exp_to", &f.EXPTo},
+		{"issued_from", &f.IssuedFrom},
+		{"issued_to", &f.IssuedTo},
+	} {
+		v := strings.TrimSpace(q.Get(p.key))
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return issvc.PassFilter{}, ErrInvalidTime
+		}
+		t = t.UTC()
+		*p.dst = &t
+	}
+	return f, nil
+}
+
+// ParseListPassesQuery читает фильтры, курсор и размер страницы
+func ParseListPassesQuery(q url.Values) (issvc.ListPassesQuery, error) {
+	f, err := ParsePassFilter(q)
+	if err != nil {
+		return issvc.ListPassesQuery{}, err
+	}
+	out := issvc.ListPassesQuery{Filter: f, Cursor: strings.TrimSpace(q.Get("cursor"))}
+	if v := strings.TrimSpace(q.Get("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > issvc.MaxListLimit {
+			return issvc.ListPassesQuery{}, ErrInvalidLimit
+		}
+		out.Limit = n
+	}
+	return out, nil
+}
+
+// ParseRevocationQuery читает org_id и since для списка отзывов
+func ParseRevocationQuery(q url.Values) (orgID string, since int64, err error) {
+	orgID = strings.TrimSpace(q.Get("org_id"))
+	if orgID != "" {
+		if _, err := uuid.Parse(orgID); err != nil {
+			return "", 0, ErrInvalidOrgID
+		}
+	}
+	if v := strings.TrimSpace(q.Get("since")); v != "" {
+		since, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || since < 0 {
+			return "", 0, ErrInvalidSince
+		}
+	}
+	return orgID, since, nil
+}
+
+// ParseListZonesQuery читает org_id и parent_id списка зон
+func ParseListZonesQuery(q url.Values) (orgID, parentID string, err error) {
+	orgID = strings.TrimSpace(q.Get("org_id"))
+	if orgID != "" {
+		if _, err := uuid.Parse(orgID); err != nil {
+			return "", "", ErrInvalidOrgID
+		}
+	}
+	return orgID, strings.TrimSpace(q.Get("parent_id")), nil
+}
+
+// ParseOrgQuery читает обязательный org_id операций над зоной или политикой по id:
+// id уникальны только внутри организации
+func ParseOrgQuery(q url.Values) (string, error) {
+	orgID := strings.TrimSpace(q.Get("org_id"))
+	if orgID == "" {
+		return "", ErrOrgRequired
+	}
+	if _, err := uuid.Parse(orgID); err != nil {
+		return "", ErrInvalidOrgID
+	}
+	return orgID, nil
+}
+
+// ParseListPoliciesQuery читает org_id списка политик
+func ParseListPoliciesQuery(q url.Values) (orgID string, err error) {
+	orgID = strings.TrimSpace(q.Get("org_id"))
+	if orgID != "" {
+		if _, err := uuid.Parse(orgID); err != nil {
+			return "", ErrInvalidOrgID
+		}
+	}
+	return orgID, nil
+}
+
+// ImportQuery — параметры импорта из query
+type ImportQuery struct {
+	OrgID     string
+	Location  *time.Location
+	Pickup    bool
+	PickupTTL time.Duration
+}
+
+// ParseImportQuery читает параметры импорта: org_id по умолчанию для строк, пояс IANA для дат
+// без смещения (по умолчанию UTC), pickup — выдавать ли pickup-токены (по умолчанию да)
+// и pickup_ttl_h — их срок в часах (по умолчанию importer.DefaultPickupTTL)
+func ParseImportQuery(q url.Values) (ImportQuery, error) {
+	out := ImportQuery{OrgID: strings.TrimSpace(q.Get("org_id")), Location: time.UTC, Pickup: true, PickupTTL: importer.DefaultPickupTTL}
+	if out.OrgID != "" {
+		if _, err := uuid.Parse(out.OrgID); err != nil {
+			return ImportQuery{}, ErrInvalidOrgID
+		}
+	}
+	if tz := strings.TrimSpace(q.Get("tz")); tz != "" {
+		// Local — пояс сервера, результат зависел бы от его настройки
+		loc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			return ImportQuery{}, ErrInvalidTZ
+		}
+		out.Location = loc
+	}
+	if v := strings.TrimSpace(q.Get("pickup")); v != "" {
+		pickup, err := strconv.ParseBool(v)
+		if err != nil {
+			return ImportQuery{}, ErrInvalidPickup
+		}
+		out.Pickup = pickup
+	}
+	if v := strings.TrimSpace(q.Get("pickup_ttl_h")); v != "" {
+		h, err := strconv.Atoi(v)
+		if err != nil || h <= 0 || time.Duration(h)*time.Hour > importer.MaxPickupTTL {
+			return ImportQuery{}, ErrInvalidPickupTTL
+		}
+		out.PickupTTL = time.Duration(h) * time.Hour
+	}
+	return out, nil
+}
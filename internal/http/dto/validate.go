@@ -2,27 +2,224 @@ package dto
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+	"github.com/vbncursed/vkr/issue-service/internal/qr"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
 )
 
 var (
-	ErrZoneRequired     = errors.New("zone_id required")
-	ErrNbfAfterExp      = errors.New("nbf must be before exp")
-	ErrExpExceedsMaxTTL = errors.New("exp exceeds max ttl")
-	ErrTokenRequired    = errors.New("token required")
+	ErrZoneRequired    = errors.New("zone_id required")
+	ErrNbfAfterExp     = issvc.ErrNbfAfterExp
+	ErrTokenRequired   = errors.New("token required")
+	ErrPayloadRequired = errors.New("payload required")
+	ErrReaderRequired  = errors.New("reader_id required")
+	ErrInvalidMaxUses  = issvc.ErrInvalidMaxUses
+	ErrInvalidReason   = errors.New("invalid revocation reason")
+	ErrNoteTooLong     = errors.New("note too long")
+	ErrTooManyZones    = issvc.ErrTooManyZones
+	ErrInvalidZoneID   = errors.New("invalid zone id")
+	ErrInvalidZoneKind = errors.New("invalid zone kind")
+	ErrNameRequired    = errors.New("name required")
+	ErrEmptyPatch      = errors.New("nothing to change")
+	ErrSubjectRequired = errors.New("subject_name required")
+	ErrExpInPast       = errors.New("exp must be in the future")
+	ErrFilterRequired  = errors.New("at least one filter besides org_id required")
+	ErrInvalidPolicyID = errors.New("invalid policy id")
+	ErrInvalidMaxTTL   = errors.New("invalid max_ttl_s")
+	ErrSchemaTooLarge  = errors.New("attrs_schema too large")
+	ErrInvalidIdemKey  = errors.New("invalid idempotency key")
+	ErrBatchEmpty      = errors.New("batch is empty")
+	ErrBatchTooLarge   = errors.New("batch too large")
 )
 
-// Validate проверяет инварианты CreatePassRequest
-func (r CreatePassRequest) Validate(now time.Time, maxTTL time.Duration) error {
-	if strings.TrimSpace(r.ZoneID) == "" {
+// MaxZones — предел числа зон одного пропуска
+const MaxZones = issvc.MaxZones
+
+// validateZones — после нормализации остаётся от 1 до MaxZones зон
+func validateZones(zones []string) error {
+	n := len(zones)
+	if n == 0 {
 		return ErrZoneRequired
 	}
-	if !r.NBF.Before(r.EXP) {
-		return ErrNbfAfterExp
+	if n > MaxZones {
+		return ErrTooManyZones
+	}
+	return nil
+}
+
+// Validate проверяет инварианты CreatePassRequest; нужна zone_id или непустой zone_ids.
+// Срок жизни ограничивает политика — проверка в сервисе.
+func (r CreatePassRequest) Validate() error {
+	if err := validateZones(zoneList(r.ZoneID, r.ZoneIDs)); err != nil {
+		return err
+	}
+	return r.ToCommand("").Validate()
+}
+
+// MaxIdempotencyKeyLen — предел длины Idempotency-Key
+const MaxIdempotencyKeyLen = 255
+
+// ValidateIdempotencyKey — печатные ASCII-символы без пробелов, не длиннее MaxIdempotencyKeyLen
+func ValidateIdempotencyKey(key string) error {
+	if key == "" || len(key) > MaxIdempotencyKeyLen {
+		return ErrInvalidIdemKey
 	}
-	if r.EXP.Sub(now.UTC()) > maxTTL {
-		return ErrExpExceedsMaxTTL
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] > '~' {
+			return ErrInvalidIdemKey
+		}
+	}
+	return nil
+}
+
+// MaxNoteLen — предел длины комментария к отзыву
+const MaxNoteLen = 1024
+
+// Validate проверяет инварианты RevokeRequest; пустая причина допустима (other)
+func (r RevokeRequest) Validate() error {
+	if r.Reason != "" && !im.RevocationReason(r.Reason).Valid() {
+		return ErrInvalidReason
+	}
+	if len(r.Note) > MaxNoteLen {
+		return ErrNoteTooLong
+	}
+	return nil
+}
+
+// Validate проверяет инварианты AmendPassRequest: хотя бы одно поле, зона и имя не пустые
+func (r AmendPassRequest) Validate() error {
+	if r.ZoneID == nil && r.ZoneIDs == nil && r.SubjectName == nil && r.Attrs == nil {
+		return ErrEmptyPatch
+	}
+	if zones, ok := r.zones(); ok {
+		if err := validateZones(zones); err != nil {
+			return err
+		}
+	}
+	if r.SubjectName != nil && strings.TrimSpace(*r.SubjectName) == "" {
+		return ErrSubjectRequired
+	}
+	return nil
+}
+
+// Validate проверяет инварианты RenewRequest; сравнение с текущим exp и TTL политики — в сервисе
+func (r RenewRequest) Validate(now time.Time) error {
+	if !r.EXP.After(now.UTC()) {
+		return ErrExpInPast
+	}
+	return nil
+}
+
+// MaxBatchItems — предел числа элементов пакетного выпуска
+const MaxBatchItems = 500
+
+// Validate проверяет размер пакета; элементы проверяются по отдельности, чтобы ошибка
+// одного попала в его результат
+func (r BatchCreatePassesRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return ErrBatchEmpty
+	}
+	if len(r.Items) > MaxBatchItems {
+		return ErrBatchTooLarge
+	}
+	return nil
+}
+
+// Validate проверяет инварианты BulkRevokeRequest: org_id обязателен, одного его мало
+func (r BulkRevokeRequest) Validate() error {
+	if _, err := uuid.Parse(strings.TrimSpace(r.OrgID)); err != nil {
+		return ErrInvalidOrgID
+	}
+	if strings.TrimSpace(r.SubjectName) == "" && strings.TrimSpace(r.ZoneID) == "" &&
+		strings.TrimSpace(r.PolicyID) == "" && strings.TrimSpace(r.IssuerKeyID) == "" {
+		return ErrFilterRequired
+	}
+	return RevokeRequest{Reason: r.Reason, Note: r.Note}.Validate()
+}
+
+// Validate проверяет инварианты CompromiseKeyRequest
+func (r CompromiseKeyRequest) Validate() error {
+	if len(r.Note) > MaxNoteLen {
+		return ErrNoteTooLong
+	}
+	return nil
+}
+
+// zoneIDPattern — латиница, цифры, '.', '_', '-'; без пробелов, чтобы "A1 " не стала отдельной зоной
+var zoneIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Validate проверяет инварианты CreateZoneRequest; иерархию проверяет сервис
+func (r CreateZoneRequest) Validate() error {
+	if !zoneIDPattern.MatchString(r.ID) {
+		return ErrInvalidZoneID
+	}
+	if _, err := uuid.Parse(strings.TrimSpace(r.OrgID)); err != nil {
+		return ErrInvalidOrgID
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrNameRequired
+	}
+	if !im.ZoneKind(r.Kind).Valid() {
+		return ErrInvalidZoneKind
+	}
+	return nil
+}
+
+// Validate проверяет инварианты UpdateZoneRequest
+func (r UpdateZoneRequest) Validate() error {
+	if r.Name == nil && r.ParentID == nil {
+		return ErrEmptyPatch
+	}
+	if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
+		return ErrNameRequired
+	}
+	return nil
+}
+
+// Validate проверяет инварианты CreatePolicyRequest; id политики — по тем же правилам, что id зоны
+func (r CreatePolicyRequest) Validate() error {
+	if !zoneIDPattern.MatchString(r.ID) {
+		return ErrInvalidPolicyID
+	}
+	if _, err := uuid.Parse(strings.TrimSpace(r.OrgID)); err != nil {
+		return ErrInvalidOrgID
+	}
+	return r.PolicySpec.Validate()
+}
+
+// Validate проверяет инварианты PolicySpec; существование зон и корректность схемы проверяет сервис
+func (r PolicySpec) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrNameRequired
+	}
+	if r.MaxTTLSeconds < 0 {
+		return ErrInvalidMaxTTL
+	}
+	if len(trimList(r.AllowedZones)) > MaxZones {
+		return ErrTooManyZones
+	}
+	if len(r.AttrsSchema) > MaxSchemaLen {
+		return ErrSchemaTooLarge
+	}
+	return nil
+}
+
+// MaxSchemaLen — предел размера attrs_schema политики в байтах
+const MaxSchemaLen = 64 << 10
+
+// Validate проверяет инварианты RedeemRequest
+func (r RedeemRequest) Validate() error {
+	if strings.TrimSpace(r.ReaderID) == "" {
+		return ErrReaderRequired
+	}
+	if strings.TrimSpace(r.ZoneID) == "" {
+		return ErrZoneRequired
 	}
 	return nil
 }
@@ -32,5 +229,30 @@ func (r PickupRequest) Validate() error {
 	if strings.TrimSpace(r.Token) == "" {
 		return ErrTokenRequired
 	}
+	_, err := r.QROptions()
+	return err
+}
+
+// QROptions — параметры QR-кода из запроса с умолчаниями; nil, если QR не запрошен
+func (r PickupRequest) QROptions() (*qr.Options, error) {
+	if r.QR == nil {
+		return nil, nil
+	}
+	size := ""
+	if r.QR.Size != 0 {
+		size = strconv.Itoa(r.QR.Size)
+	}
+	o, err := qr.ParseOptions(r.QR.Format, size, r.QR.ECC)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Validate проверяет инварианты VerifyRequest
+func (r VerifyRequest) Validate() error {
+	if strings.TrimSpace(r.Payload) == "" {
+		return ErrPayloadRequired
+	}
 	return nil
 }
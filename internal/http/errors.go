@@ -2,9 +2,13 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	"github.com/vbncursed/vkr/issue-service/internal/importer"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+	"github.com/vbncursed/vkr/issue-service/internal/qr"
 	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
 )
 
@@ -14,24 +18,152 @@ func MapError(err error) (int, APIError) {
 	// DTO validation
 	case errors.Is(err, dto.ErrZoneRequired):
 		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "zone_id required"}
+	case errors.Is(err, dto.ErrTooManyZones):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "too many zones"}
+	case errors.Is(err, issvc.ErrNoZones):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "zone_id required"}
 	case errors.Is(err, dto.ErrNbfAfterExp):
 		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "nbf must be before exp"}
-	case errors.Is(err, dto.ErrExpExceedsMaxTTL):
-		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "exp exceeds max ttl"}
+	case errors.Is(err, dto.ErrEmptyPatch), errors.Is(err, issvc.ErrNothingToAmend):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "nothing to change"}
+	case errors.Is(err, dto.ErrInvalidZoneID):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "zone id must match [A-Za-z0-9][A-Za-z0-9._-]{0,63}"}
+	case errors.Is(err, dto.ErrInvalidPolicyID):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "policy id must match [A-Za-z0-9][A-Za-z0-9._-]{0,63}"}
+	case errors.Is(err, dto.ErrInvalidMaxTTL):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "max_ttl_s must be >= 0"}
+	case errors.Is(err, dto.ErrSchemaTooLarge):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "attrs_schema too large"}
+	case errors.Is(err, dto.ErrInvalidZoneKind):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "kind must be one of building, floor, room"}
+	case errors.Is(err, dto.ErrNameRequired):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "name required"}
+	case errors.Is(err, dto.ErrSubjectRequired):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "subject_name required"}
+	case errors.Is(err, dto.ErrExpInPast):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "exp must be in the future"}
+	case errors.Is(err, dto.ErrInvalidMaxUses):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "max_uses must be >= 0 and at most 1 for one_time"}
+	case errors.Is(err, dto.ErrInvalidReason), errors.Is(err, issvc.ErrInvalidReason):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "reason must be one of lost, compromised, policy_change, superseded, other"}
+	case errors.Is(err, dto.ErrNoteTooLong):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "note too long"}
+	case errors.Is(err, dto.ErrFilterRequired), errors.Is(err, issvc.ErrEmptyFilter):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "at least one of subject_name, zone_id, policy_id, issuer_key_id required"}
+	case errors.Is(err, im.ErrInvalidSchedule):
+		return http.StatusBadRequest, APIError{Code: "invalid_schedule", Message: err.Error()}
+	case errors.Is(err, dto.ErrInvalidIdemKey):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "Idempotency-Key must be 1-255 printable ASCII characters without spaces"}
+	case errors.Is(err, dto.ErrBatchEmpty):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "items required"}
+	case errors.Is(err, dto.ErrBatchTooLarge):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "too many items"}
+	case errors.Is(err, qr.ErrInvalidFormat):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "qr format must be png or svg"}
+	case errors.Is(err, qr.ErrInvalidSize):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: fmt.Sprintf("qr size must be from %d to %d", qr.MinSize, qr.MaxSize)}
+	case errors.Is(err, qr.ErrInvalidLevel):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "qr ecc must be one of L, M, Q, H"}
+	case errors.Is(err, qr.ErrTooLarge):
+		return http.StatusUnprocessableEntity, APIError{Code: "qr_too_large", Message: "payload does not fit into a QR code at this ecc level"}
 	case errors.Is(err, dto.ErrTokenRequired):
 		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "token required"}
+	case errors.Is(err, dto.ErrReaderRequired):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "reader_id required"}
+	case errors.Is(err, dto.ErrPayloadRequired):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "payload required"}
+	case errors.Is(err, dto.ErrInvalidOrgID):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "invalid org_id"}
+	case errors.Is(err, dto.ErrInvalidStatus):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "invalid status"}
+	case errors.Is(err, dto.ErrInvalidTime):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "time filters must be RFC3339"}
+	case errors.Is(err, dto.ErrInvalidLimit):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "invalid limit"}
+	case errors.Is(err, dto.ErrInvalidSince):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "since must be a non-negative integer"}
+	case errors.Is(err, dto.ErrOrgRequired):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "org_id required"}
+	case errors.Is(err, dto.ErrInvalidFormat):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "format must be csv or ndjson"}
+	case errors.Is(err, dto.ErrInvalidTZ):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "tz must be an IANA time zone name"}
+	case errors.Is(err, dto.ErrInvalidPickup):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "pickup must be true or false"}
+	case errors.Is(err, dto.ErrInvalidPickupTTL):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "pickup_ttl_h must be an integer from 1 to 168"}
+	case errors.Is(err, importer.ErrInvalidFile):
+		return http.StatusBadRequest, APIError{Code: "invalid_import_file", Message: err.Error()}
+	case errors.Is(err, importer.ErrInvalidField):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: err.Error()}
 
 	// Service errors
+	case errors.Is(err, issvc.ErrNoActiveKey):
+		return http.StatusServiceUnavailable, APIError{Code: "no_active_key", Message: "no active issuer key"}
 	case errors.Is(err, issvc.ErrUnsupportedAlg):
 		return http.StatusServiceUnavailable, APIError{Code: "unsupported_alg", Message: "only EdDSA supported"}
 	case errors.Is(err, issvc.ErrNotFound):
 		return http.StatusNotFound, APIError{Code: "not_found", Message: "pass not found"}
+	case errors.Is(err, issvc.ErrNotExtended):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "exp must be later than current exp"}
+	case errors.Is(err, issvc.ErrUnknownZone):
+		return http.StatusBadRequest, APIError{Code: "unknown_zone", Message: err.Error()}
+	case errors.Is(err, issvc.ErrInvalidParent):
+		return http.StatusBadRequest, APIError{Code: "invalid_parent", Message: "parent must be a zone one level up (building > floor > room) in the same org"}
+	case errors.Is(err, issvc.ErrZoneNotFound):
+		return http.StatusNotFound, APIError{Code: "not_found", Message: "zone not found"}
+	case errors.Is(err, issvc.ErrZoneExists):
+		return http.StatusConflict, APIError{Code: "zone_exists", Message: "zone id already taken"}
+	case errors.Is(err, issvc.ErrZoneInUse):
+		return http.StatusConflict, APIError{Code: "zone_in_use", Message: "zone has child zones or active passes"}
+	case errors.Is(err, issvc.ErrPolicyViolation):
+		var pv *issvc.PolicyViolationError
+		if errors.As(err, &pv) {
+			return http.StatusUnprocessableEntity, APIError{Code: "policy_violation", Message: "pass violates policy " + pv.PolicyID, Details: dto.FromViolations(pv.Violations)}
+		}
+		return http.StatusUnprocessableEntity, APIError{Code: "policy_violation", Message: "pass violates policy"}
+	case errors.Is(err, issvc.ErrInvalidSchema):
+		return http.StatusBadRequest, APIError{Code: "invalid_attrs_schema", Message: err.Error()}
+	case errors.Is(err, issvc.ErrUnknownPolicy):
+		return http.StatusBadRequest, APIError{Code: "unknown_policy", Message: "policy_id is not registered for this org"}
+	case errors.Is(err, issvc.ErrIssuerDenied):
+		return http.StatusForbidden, APIError{Code: "issuer_not_allowed", Message: "X-Actor may not issue passes under this policy"}
+	case errors.Is(err, issvc.ErrPolicyNotFound):
+		return http.StatusNotFound, APIError{Code: "not_found", Message: "policy not found"}
+	case errors.Is(err, issvc.ErrPolicyExists):
+		return http.StatusConflict, APIError{Code: "policy_exists", Message: "policy id already taken"}
+	case errors.Is(err, issvc.ErrPolicyInUse):
+		return http.StatusConflict, APIError{Code: "policy_in_use", Message: "policy has active passes"}
+	case errors.Is(err, issvc.ErrIdemMismatch):
+		return http.StatusUnprocessableEntity, APIError{Code: "idempotency_key_reused", Message: "Idempotency-Key was already used with a different request"}
+	case errors.Is(err, issvc.ErrIdemInProgress):
+		return http.StatusConflict, APIError{Code: "idempotency_key_in_progress", Message: "a request with this Idempotency-Key is still being processed"}
+	case errors.Is(err, issvc.ErrBatchAborted):
+		return http.StatusConflict, APIError{Code: "batch_aborted", Message: "not issued: another item failed and all_or_nothing is set"}
+	case errors.Is(err, issvc.ErrKeyNotFound):
+		return http.StatusNotFound, APIError{Code: "not_found", Message: "issuer key not found"}
 	case errors.Is(err, issvc.ErrConflict):
-		return http.StatusConflict, APIError{Code: "conflict", Message: "not Active"}
+		var zr *issvc.ZoneReferencedError
+		if errors.As(err, &zr) {
+			return http.StatusConflict, APIError{Code: "zone_in_use", Message: zr.Error()}
+		}
+		return http.StatusConflict, APIError{Code: "conflict", Message: "transition not allowed from current status"}
 	case errors.Is(err, issvc.ErrExpiredOrUsed):
 		return http.StatusBadRequest, APIError{Code: "invalid_token", Message: "expired_or_used"}
 	case errors.Is(err, issvc.ErrInvalidToken):
 		return http.StatusBadRequest, APIError{Code: "invalid_token", Message: "invalid"}
+	case errors.Is(err, issvc.ErrAlreadyRedeemed):
+		return http.StatusConflict, APIError{Code: "already_redeemed", Message: "one-time pass already used"}
+	case errors.Is(err, issvc.ErrExhausted):
+		return http.StatusConflict, APIError{Code: "exhausted", Message: "pass has no uses left"}
+	case errors.Is(err, issvc.ErrOutsideValidity):
+		return http.StatusConflict, APIError{Code: "outside_validity", Message: "pass is not valid at this time"}
+	case errors.Is(err, issvc.ErrOutsideSchedule):
+		return http.StatusConflict, APIError{Code: "outside_schedule", Message: "pass schedule has no open window now"}
+	case errors.Is(err, issvc.ErrZoneNotAllowed):
+		return http.StatusForbidden, APIError{Code: "zone_not_allowed", Message: "zone not in pass scopes"}
+	case errors.Is(err, issvc.ErrInvalidCursor):
+		return http.StatusBadRequest, APIError{Code: "invalid_request", Message: "invalid cursor"}
 	}
 	return http.StatusInternalServerError, APIError{Code: "internal", Message: "internal error"}
 }
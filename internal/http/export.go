@@ -0,0 +1,122 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// exportFlushEvery — через сколько строк выгрузки ответ отправляется клиенту
+const exportFlushEvery = 500
+
+// ExportPasses — потоковая выгрузка пропусков организации
+// @Summary     Выгрузка пропусков
+// @Description Все пропуска по фильтрам списка (org_id обязателен) в порядке выпуска, без пагинации: строки читаются из базы серверным курсором и сразу пишутся в ответ. format=csv (по умолчанию) — колонки id, org_id, policy_id, subject_name, zone_ids (через пробел), nbf, exp, one_time, max_uses, uses, version, status, issuer_key_id, created_at, revoked_at, revocation_reason, revocation_note, revoked_by, suspended_at, suspended_by, replaces_id; format=ndjson — по объекту GET /passes/{id} (с payload) на строку. Если выгрузка оборвалась после начала ответа, соединение разрывается — неполный файл не выглядит завершённым.
+// @Tags        passes
+// @Produce     text/csv
+// @Produce     application/x-ndjson
+// @Param       org_id        query string true  "Org ID"
+// @Param       format        query string false "csv|ndjson"
+// @Param       policy_id     query string false "Policy ID"
+// @Param       zone_id       query string false "Zone ID"
+// @Param       status        query string false "Active|Suspended|Revoked|Expired"
+// @Param       subject_name  query string false "Префикс subject_name"
+// @Param       nbf_from      query string false "nbf >= (RFC3339)"
+// @Param       nbf_to        query string false "nbf < (RFC3339)"
+// @Param       exp_from      query string false "exp >= (RFC3339)"
+// @Param       exp_to        query string false "exp < (RFC3339)"
+// @Param       issued_from   query string false "created_at >= (RFC3339)"
+// @Param       issued_to     query string false "created_at < (RFC3339)"
+// @Success     200 {file} file "Выгрузка"
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes:export [get]
+func ExportPasses(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		f, format, err := dto.ParseExportQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+
+		res := c.Response()
+		var write func(v issvc.PassView) error
+		var flush func() error
+		var cw *csv.Writer
+		switch format {
+		case dto.ExportNDJSON:
+			enc := json.NewEncoder(res)
+			write = func(v issvc.PassView) error { return enc.Encode(dto.FromPassView(v)) }
+			flush = func() error { return nil }
+		default:
+			cw = csv.NewWriter(res)
+			write = func(v issvc.PassView) error { return cw.Write(dto.ExportCSVRecord(v)) }
+			flush = func() error {
+				cw.Flush()
+				return cw.Error()
+			}
+		}
+		// заголовки ответа отправляются с первой строкой: до неё ошибку ещё можно вернуть обычным ответом
+		started := false
+		start := func() error {
+			started = true
+			h := res.Header()
+			h.Set(echo.HeaderCacheControl, "no-store")
+			if cw == nil {
+				h.Set(echo.HeaderContentType, "application/x-ndjson")
+				h.Set(echo.HeaderContentDisposition, `attachment; filename="passes-`+f.OrgID+`.ndjson"`)
+				res.WriteHeader(http.StatusOK)
+				return nil
+			}
+			h.Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+			h.Set(echo.HeaderContentDisposition, `attachment; filename="passes-`+f.OrgID+`.csv"`)
+			res.WriteHeader(http.StatusOK)
+			// BOM — чтобы Excel открыл кириллицу
+			if _, err := io.WriteString(res, "\xef\xbb\xbf"); err != nil {
+				return err
+			}
+			return cw.Write(dto.ExportCSVHeader)
+		}
+
+		n := 0
+		err = svc.ExportPasses(c.Request().Context(), f, func(v issvc.PassView) error {
+			if !started {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+			if err := write(v); err != nil {
+				return err
+			}
+			if n++; n%exportFlushEvery == 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+				res.Flush()
+			}
+			return nil
+		})
+		if err == nil && !started {
+			err = start()
+		}
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			if !started {
+				status, apiErr := MapError(err)
+				return writeJSON(c, status, apiErr)
+			}
+			// статус 200 уже отправлен: обрываем соединение, чтобы клиент не принял часть за всю выгрузку
+			c.Logger().Errorf("export passes: %v", err)
+			panic(http.ErrAbortHandler)
+		}
+		return nil
+	}
+}
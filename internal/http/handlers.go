@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"mime"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -66,3 +68,26 @@ func (StrictJSONBinder) Bind(i interface{}, c echo.Context) error {
 	}
 	return nil
 }
+
+// HeaderActor — заголовок с идентификатором инициатора административных действий
+const HeaderActor = "X-Actor"
+
+// actorFromRequest — инициатор действия из X-Actor (аутентификация — на стороне шлюза)
+func actorFromRequest(c echo.Context) string {
+	return strings.TrimSpace(c.Request().Header.Get(HeaderActor))
+}
+
+// passIDParam — id пропуска из пути; не UUID — false, чтобы не отдавать его в базу
+func passIDParam(c echo.Context) (string, bool) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return "", false
+	}
+	return id.String(), true
+}
+
+// Заголовки идемпотентного выпуска: ключ от клиента и признак повторённого ответа
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderReplayed       = "Idempotent-Replayed"
+)
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	"github.com/vbncursed/vkr/issue-service/internal/importer"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// Заголовки ответа импорта с итогом по строкам
+const (
+	HeaderImportIssued = "X-Import-Issued"
+	HeaderImportFailed = "X-Import-Failed"
+)
+
+// ImportPasses — выпуск пропусков по списку посетителей в CSV
+// @Summary     Импорт списка посетителей
+// @Description Только CSV с заголовком (разделитель ',' или ';', UTF-8, BOM допустим) — телом text/csv или полем file в multipart/form-data; XLSX не поддерживается — сохраните таблицу как CSV UTF-8, иначе 400 invalid_import_file. Колонки: subject_name, zone (несколько — через пробел или ';'), nbf, exp, policy; необязательные org_id, max_uses, one_time, attrs (JSON-объект) и attr.<имя> (строковый атрибут). Даты — RFC3339 или YYYY-MM-DD HH:MM / DD.MM.YYYY HH:MM в поясе tz; дата без времени в exp — до конца дня. Каждая строка проверяется как POST /passes. Ответ — файл результата CSV с тем же разделителем: line, subject_name, status (issued|failed), pass_id, pickup_token, pickup_expires_at, error_code, error_message. Ошибки строк не мешают остальным; ошибка структуры файла — 400 invalid_import_file.
+// @Tags        passes
+// @Accept      text/csv
+// @Accept      multipart/form-data
+// @Produce     text/csv
+// @Produce     json
+// @Param       X-Actor      header   string  false "Инициатор; сверяется с issuers политик"
+// @Param       org_id       query    string  false "Организация строк без колонки org_id"
+// @Param       tz           query    string  false "Пояс IANA для дат без смещения (по умолчанию UTC)"
+// @Param       pickup       query    bool    false "Выдать pickup-токены (по умолчанию true)"
+// @Param       pickup_ttl_h query    integer false "Срок pickup-токенов в часах, 1–168, не дольше exp пропуска (по умолчанию 24)"
+// @Param       file         formData file    false "CSV-файл (для multipart/form-data)"
+// @Success     200 {file} file "Файл результата"
+// @Header      200 {integer} X-Import-Issued "Выпущено пропусков"
+// @Header      200 {integer} X-Import-Failed "Строк с ошибкой"
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Failure     503 {object} APIError
+// @Router      /passes:import [post]
+func ImportPasses(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, err := dto.ParseImportQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		body, err := importBody(c)
+		if err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		defer body.Close()
+
+		opts := importer.Options{OrgID: q.OrgID, Location: q.Location, Actor: actorFromRequest(c), Pickup: q.Pickup, PickupTTL: q.PickupTTL}
+		f, err := importer.Parse(body, opts)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		results, err := importer.Run(c.Request().Context(), svc, f, opts, time.Now().UTC())
+		issued, failed := importer.Counts(results)
+		// ничего не выпущено из-за сбоя ключа или базы — обычный ответ об ошибке, а не файл
+		if err != nil && issued == 0 {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+
+		h := c.Response().Header()
+		h.Set(echo.HeaderCacheControl, "no-store")
+		h.Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		h.Set(echo.HeaderContentDisposition, `attachment; filename="import-result.csv"`)
+		h.Set(HeaderImportIssued, strconv.Itoa(issued))
+		h.Set(HeaderImportFailed, strconv.Itoa(failed))
+		c.Response().WriteHeader(http.StatusOK)
+		return importer.WriteResult(c.Response(), results, f.Comma, DescribeImportError)
+	}
+}
+
+// importBody — файл из поля file для multipart/form-data, иначе тело запроса целиком
+func importBody(c echo.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return c.Request().Body, nil
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return fh.Open()
+}
+
+// DescribeImportError — код и текст ошибки строки файла результата, как в теле ответа MapError:
+// внутренние ошибки — без подробностей. У нарушений политики details в файле нет, поэтому
+// текст перечисляет их все. Им же пишет результат cmd/import-passes.
+func DescribeImportError(err error) (code, message string) {
+	_, apiErr := MapError(err)
+	if errors.Is(err, issvc.ErrPolicyViolation) {
+		return apiErr.Code, err.Error()
+	}
+	return apiErr.Code, apiErr.Message
+}
@@ -1,7 +1,10 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -25,3 +28,41 @@ func JWKS(svc *issvc.Service) echo.HandlerFunc {
 		return writeJSON(c, http.StatusOK, dto.FromIssuerKeys(keys))
 	}
 }
+
+// CompromiseKey — аварийная процедура при утечке ключа эмитента
+// @Summary     Компрометация ключа эмитента
+// @Description Помечает ключ compromised (исчезает из JWKS, verify отвечает unknown_key) и одной транзакцией отзывает все его Active/Suspended пропуска с причиной compromised. Если ключ был активным или reissue=true, активным становится новый ключ; с reissue=true действующим Active пропускам выпускаются замены (meta.replaces, replaces_id). Ответ — отчёт по затронутым пропускам.
+// @Tags        keys
+// @Accept      json
+// @Produce     json
+// @Param       kid     path   string                   true  "Key ID"
+// @Param       X-Actor header string                   false "Инициатор"
+// @Param       request body   dto.CompromiseKeyRequest false "Options"
+// @Success     200 {object} dto.CompromiseKeyResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /admin/keys/{kid}/compromise [post]
+func CompromiseKey(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kid := strings.TrimSpace(c.Param("kid"))
+		if kid == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "kid"})
+		}
+		var req dto.CompromiseKeyRequest
+		if err := c.Bind(&req); err != nil && !errors.Is(err, io.EOF) {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		rep, err := svc.CompromiseKey(c.Request().Context(), req.ToCommand(kid, actorFromRequest(c)))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromCompromiseKeyReport(rep))
+	}
+}
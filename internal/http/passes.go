@@ -1,6 +1,8 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -9,41 +11,63 @@ import (
 
 	"github.com/vbncursed/vkr/issue-service/internal/config"
 	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
 	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
 )
 
 // CreatePass — выпуск пропуска
 // @Summary     Выпуск пропуска
+// @Description Политика policy_id должна быть зарегистрирована в организации: она задаёт level и one_time по умолчанию, а нарушения её правил (TTL, зоны, обязательные attrs, attrs_schema) возвращаются списком в details с кодом 422: field — путь вида attrs.car.plate. С Idempotency-Key (в пределах org_id и X-Actor) повтор запроса в течение IDEMPOTENCY_TTL_H возвращает исходный ответ без нового пропуска; тот же ключ с другим телом — 422, пока исходный запрос выполняется — 409.
 // @Tags        passes
 // @Accept      json
 // @Produce     json
-// @Param       request body dto.CreatePassRequest true "Create pass"
+// @Param       X-Actor         header string                false "Инициатор; сверяется с issuers политики"
+// @Param       Idempotency-Key header string                false "Ключ повтора: тот же ключ с тем же телом вернёт исходный ответ"
+// @Param       request         body   dto.CreatePassRequest true  "Create pass"
 // @Success     201 {object} dto.CreatePassResponse
+// @Header      201 {string} Idempotent-Replayed "true, если ответ повторён по Idempotency-Key"
 // @Failure     400 {object} APIError
+// @Failure     403 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     422 {object} APIError
 // @Failure     500 {object} APIError
 // @Failure     503 {object} APIError
 // @Router      /passes [post]
-func CreatePass(svc *issvc.Service, cfg config.Config) echo.HandlerFunc {
+func CreatePass(svc *issvc.Service) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		var req dto.CreatePassRequest
 		if err := c.Bind(&req); err != nil {
 			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
 		}
 		// validations (DTO level)
-		if err := req.Validate(time.Now().UTC(), cfg.MaxTTL); err != nil {
+		if err := req.Validate(); err != nil {
 			switch err {
 			case dto.ErrZoneRequired:
 				return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "zone_id required"})
 			case dto.ErrNbfAfterExp:
 				return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "nbf must be before exp"})
-			case dto.ErrExpExceedsMaxTTL:
-				return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "exp exceeds max ttl"})
 			default:
-				return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+				status, apiErr := MapError(err)
+				return writeJSON(c, status, apiErr)
 			}
 		}
 
-		res, err := svc.IssuePass(c.Request().Context(), req.ToCommand())
+		cmd := req.ToCommand(actorFromRequest(c))
+		var res issvc.IssuePassResult
+		var err error
+		if key := c.Request().Header.Get(HeaderIdempotencyKey); key != "" {
+			if err := dto.ValidateIdempotencyKey(key); err != nil {
+				status, apiErr := MapError(err)
+				return writeJSON(c, status, apiErr)
+			}
+			var replayed bool
+			res, replayed, err = svc.IssuePassOnce(c.Request().Context(), key, cmd)
+			if replayed {
+				c.Response().Header().Set(HeaderReplayed, "true")
+			}
+		} else {
+			res, err = svc.IssuePass(c.Request().Context(), cmd)
+		}
 		if err != nil {
 			status, apiErr := MapError(err)
 			return writeJSON(c, status, apiErr)
@@ -52,27 +76,392 @@ func CreatePass(svc *issvc.Service, cfg config.Config) echo.HandlerFunc {
 	}
 }
 
+// BatchCreatePasses — пакетный выпуск пропусков
+// @Summary     Пакетный выпуск пропусков
+// @Description До 500 элементов в формате POST /passes. Каждый элемент проверяется отдельно, все выпущенные подписываются одним активным ключом и записываются одной транзакцией. Ответ 200 содержит итог по каждому индексу: issued с payload или failed с ошибкой в формате APIError. С all_or_nothing=true ошибка любого элемента отменяет пакет: остальные элементы получают status skipped и код batch_aborted.
+// @Tags        passes
+// @Accept      json
+// @Produce     json
+// @Param       X-Actor  header string                         false "Инициатор; сверяется с issuers политик"
+// @Param       request  body   dto.BatchCreatePassesRequest   true  "Batch"
+// @Success     200 {object} dto.BatchCreatePassesResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Failure     503 {object} APIError
+// @Router      /passes:batch [post]
+func BatchCreatePasses(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req dto.BatchCreatePassesRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+
+		actor := actorFromRequest(c)
+		items := make([]issvc.IssueBatchItem, len(req.Items))
+		cmds := make([]issvc.IssuePassCommand, 0, len(req.Items))
+		pos := make([]int, 0, len(req.Items))
+		invalid := false
+		for i, it := range req.Items {
+			if err := it.Validate(); err != nil {
+				items[i].Err = err
+				invalid = true
+				continue
+			}
+			cmds = append(cmds, it.ToCommand(actor))
+			pos = append(pos, i)
+		}
+		switch {
+		case invalid && req.AllOrNothing:
+			issvc.AbortBatch(items)
+		case len(cmds) > 0:
+			res, err := svc.IssuePassBatch(c.Request().Context(), cmds, req.AllOrNothing)
+			if err != nil {
+				status, apiErr := MapError(err)
+				return writeJSON(c, status, apiErr)
+			}
+			for j, r := range res {
+				items[pos[j]] = r
+			}
+		}
+		return writeJSON(c, http.StatusOK, batchResponse(items))
+	}
+}
+
+// batchResponse — итог пакета по элементам; ошибки переводятся так же, как ответы MapError
+func batchResponse(items []issvc.IssueBatchItem) dto.BatchCreatePassesResponse {
+	out := dto.BatchCreatePassesResponse{Items: make([]dto.BatchItemResult, 0, len(items))}
+	for i, it := range items {
+		if it.Err == nil {
+			out.Issued++
+			out.Items = append(out.Items, dto.BatchItemResult{
+				Index:       i,
+				Status:      "issued",
+				ID:          it.Result.ID,
+				IssuerKeyID: it.Result.IssuerKeyID,
+				Payload:     it.Result.Payload,
+			})
+			continue
+		}
+		status := "failed"
+		if errors.Is(it.Err, issvc.ErrBatchAborted) {
+			status = "skipped"
+			out.Skipped++
+		} else {
+			out.Failed++
+		}
+		_, apiErr := MapError(it.Err)
+		out.Items = append(out.Items, dto.BatchItemResult{
+			Index:  i,
+			Status: status,
+			Error:  &dto.BatchItemError{Code: apiErr.Code, Message: apiErr.Message, Details: apiErr.Details},
+		})
+	}
+	return out
+}
+
+// ListPasses — список пропусков с фильтрами и курсорной пагинацией
+// @Summary     Список пропусков
+// @Tags        passes
+// @Produce     json
+// @Param       org_id        query string false "Org ID"
+// @Param       policy_id     query string false "Policy ID"
+// @Param       zone_id       query string false "Zone ID"
+// @Param       status        query string false "Active|Suspended|Revoked|Expired"
+// @Param       subject_name  query string false "Префикс subject_name"
+// @Param       nbf_from      query string false "nbf >= (RFC3339)"
+// @Param       nbf_to        query string false "nbf < (RFC3339)"
+// @Param       exp_from      query string false "exp >= (RFC3339)"
+// @Param       exp_to        query string false "exp < (RFC3339)"
+// @Param       issued_from   query string false "created_at >= (RFC3339)"
+// @Param       issued_to     query string false "created_at < (RFC3339)"
+// @Param       cursor        query string false "next_cursor предыдущей страницы"
+// @Param       limit         query int    false "Размер страницы (1..200, по умолчанию 50)"
+// @Success     200 {object} dto.ListPassesResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes [get]
+func ListPasses(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, err := dto.ParseListPassesQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.ListPasses(c.Request().Context(), q)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromListResult(res))
+	}
+}
+
+// GetPass — карточка пропуска
+// @Summary     Получить пропуск
+// @Tags        passes
+// @Produce     json
+// @Param       id  path string true "Pass ID"
+// @Success     200 {object} dto.PassResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes/{id} [get]
+func GetPass(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		v, err := svc.GetPass(c.Request().Context(), id)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromPassView(v))
+	}
+}
+
 // RevokePass — отзыв пропуска
 // @Summary     Отзыв пропуска
+// @Description Тело необязательно; без reason причина — other. Инициатор берётся из заголовка X-Actor.
 // @Tags        passes
+// @Accept      json
 // @Produce     json
-// @Param       id  path string true "Pass ID"
+// @Param       id       path   string            true  "Pass ID"
+// @Param       X-Actor  header string            false "Инициатор отзыва"
+// @Param       request  body   dto.RevokeRequest false "Revoke"
 // @Success     200 {object} dto.RevokeResponse
+// @Failure     400 {object} APIError
 // @Failure     404 {object} APIError
 // @Failure     409 {object} APIError
 // @Failure     500 {object} APIError
 // @Router      /passes/{id}/revoke [post]
 func RevokePass(svc *issvc.Service) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id := strings.TrimSpace(c.Param("id"))
-		if id == "" {
+		id, ok := passIDParam(c)
+		if !ok {
 			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
 		}
-		if err := svc.RevokePass(c.Request().Context(), id); err != nil {
+		var req dto.RevokeRequest
+		if err := c.Bind(&req); err != nil && !errors.Is(err, io.EOF) {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
 			status, apiErr := MapError(err)
 			return writeJSON(c, status, apiErr)
 		}
-		return writeJSON(c, http.StatusOK, dto.RevokeResponseOK(id))
+		cmd := req.ToCommand(id, actorFromRequest(c))
+		if err := svc.RevokePass(c.Request().Context(), cmd); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.RevokeResponseOK(id, cmd.Reason))
+	}
+}
+
+// AmendPass — изменение атрибутов пропуска новой версией
+// @Summary     Изменить пропуск
+// @Description Меняет zone_id, subject_name (holder_hint) и/или attrs, сохраняя id пропуска: подписывается новая версия payload (pass.version+1, новый pass.status.index), прежняя переносится в pass_versions. Verify отвечает на прежнюю версию reason=superseded, в списке статусов её индекс помечен отозванным. Доступно для Active и Suspended. Новые attrs проверяются по required_attrs и attrs_schema политики (422 с details).
+// @Tags        passes
+// @Accept      json
+// @Produce     json
+// @Param       id      path   string               true  "Pass ID"
+// @Param       X-Actor header string               false "Инициатор"
+// @Param       request body   dto.AmendPassRequest true  "Changes"
+// @Success     200 {object} dto.AmendPassResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     422 {object} APIError
+// @Failure     500 {object} APIError
+// @Failure     503 {object} APIError
+// @Router      /passes/{id} [patch]
+func AmendPass(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		var req dto.AmendPassRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.AmendPass(c.Request().Context(), req.ToCommand(id, actorFromRequest(c)))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromAmendResult(res))
+	}
+}
+
+// RenewPass — продление пропуска с переподписью
+// @Summary     Продление пропуска
+// @Description Выпускает новый JWS с тем же содержимым и новым exp, подписанный активным ключом (meta.replaces — прежний id), и отзывает прежний пропуск с причиной superseded — одной транзакцией. Остаток проходов переносится. exp должен быть позже текущего и в пределах max_ttl_s политики (или MAX_TTL_H), иначе 422.
+// @Tags        passes
+// @Accept      json
+// @Produce     json
+// @Param       id      path   string           true  "Pass ID"
+// @Param       X-Actor header string           false "Инициатор"
+// @Param       request body   dto.RenewRequest true  "Renew"
+// @Success     201 {object} dto.RenewResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     422 {object} APIError
+// @Failure     500 {object} APIError
+// @Failure     503 {object} APIError
+// @Router      /passes/{id}/renew [post]
+func RenewPass(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		var req dto.RenewRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(time.Now().UTC()); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.RenewPass(c.Request().Context(), req.ToCommand(id, actorFromRequest(c)))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusCreated, dto.FromRenewResult(res))
+	}
+}
+
+// BulkRevokePasses — массовый отзыв по фильтру
+// @Summary     Массовый отзыв пропусков
+// @Description Отзывает все Active/Suspended пропуска организации, совпавшие по subject_name, zone_id, policy_id и/или issuer_key_id (точное сравнение), одной транзакцией. С dry_run=true ничего не меняет и возвращает найденные id.
+// @Tags        passes
+// @Accept      json
+// @Produce     json
+// @Param       X-Actor  header string                false "Инициатор отзыва"
+// @Param       request  body   dto.BulkRevokeRequest true  "Filter"
+// @Success     200 {object} dto.BulkRevokeResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes:revoke [post]
+func BulkRevokePasses(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req dto.BulkRevokeRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.BulkRevokePasses(c.Request().Context(), req.ToCommand(actorFromRequest(c)))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromBulkRevokeResult(res))
+	}
+}
+
+// SuspendPass — временная блокировка пропуска
+// @Summary     Приостановить пропуск
+// @Description Active → Suspended. JWS остаётся прежним; approve/pickup/redeem/verify отклоняют пропуск до reinstate.
+// @Tags        passes
+// @Produce     json
+// @Param       id      path   string true  "Pass ID"
+// @Param       X-Actor header string false "Инициатор"
+// @Success     200 {object} dto.PassStatusResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes/{id}/suspend [post]
+func SuspendPass(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		if err := svc.SuspendPass(c.Request().Context(), id, actorFromRequest(c)); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.PassStatusOK(id, im.StatusSuspended))
+	}
+}
+
+// ReinstatePass — снятие временной блокировки
+// @Summary     Восстановить пропуск
+// @Description Suspended → Active, если exp ещё не наступил.
+// @Tags        passes
+// @Produce     json
+// @Param       id  path string true "Pass ID"
+// @Success     200 {object} dto.PassStatusResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes/{id}/reinstate [post]
+func ReinstatePass(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		if err := svc.ReinstatePass(c.Request().Context(), id); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.PassStatusOK(id, im.StatusActive))
+	}
+}
+
+// RedeemPass — фиксирует проход по пропуску
+// @Summary     Погасить пропуск (проход)
+// @Description Атомарно записывает проход считывателя; повторный проход по одноразовому пропуску отклоняется.
+// @Tags        passes
+// @Accept      json
+// @Produce     json
+// @Param       id      path string            true "Pass ID"
+// @Param       request body dto.RedeemRequest true "Redeem"
+// @Success     200 {object} dto.RedeemResponse
+// @Failure     400 {object} APIError
+// @Failure     403 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes/{id}/redeem [post]
+func RedeemPass(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		var req dto.RedeemRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.RedeemPass(c.Request().Context(), req.ToCommand(id))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromRedeemResult(res))
 	}
 }
 
@@ -82,14 +471,15 @@ func RevokePass(svc *issvc.Service) echo.HandlerFunc {
 // @Produce     json
 // @Param       id  path string true "Pass ID"
 // @Success     200 {object} dto.ApproveResponse
+// @Failure     400 {object} APIError
 // @Failure     404 {object} APIError
 // @Failure     409 {object} APIError
 // @Failure     500 {object} APIError
 // @Router      /passes/{id}/approve [post]
 func ApprovePass(svc *issvc.Service, cfg config.Config) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id := strings.TrimSpace(c.Param("id"))
-		if id == "" {
+		id, ok := passIDParam(c)
+		if !ok {
 			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
 		}
 		res, err := svc.ApprovePass(c.Request().Context(), id, 1*time.Hour)
@@ -103,6 +493,7 @@ func ApprovePass(svc *issvc.Service, cfg config.Config) echo.HandlerFunc {
 
 // Pickup — вернуть payload по действующему pickup-токену
 // @Summary     Получить payload по pickup-token
+// @Description С qr {format, size, ecc} в ответе есть и QR-код payload — data URI в qr (параметры как у GET /passes/{id}/qr). Если payload не поместился в QR-код, токен всё равно погашен: payload выдаётся, а qr_error = qr_too_large.
 // @Tags        pickup
 // @Accept      json
 // @Produce     json
@@ -118,14 +509,25 @@ func Pickup(svc *issvc.Service) echo.HandlerFunc {
 			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
 		}
 		if err := req.Validate(); err != nil {
-			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "token required"})
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
 		}
+		qrOpts, _ := req.QROptions()
 		tok := strings.TrimSpace(req.Token)
 		res, err := svc.Pickup(c.Request().Context(), tok)
 		if err != nil {
 			status, apiErr := MapError(err)
 			return writeJSON(c, status, apiErr)
 		}
-		return writeJSON(c, http.StatusOK, dto.FromPickupResult(res))
+		out := dto.FromPickupResult(res)
+		if qrOpts != nil {
+			// токен уже погашен: ответ с payload отдаём всегда, сбой QR-кода — только qr_error
+			var code string
+			if out.QR, code, err = qrDataURI(res.Payload, *qrOpts); err != nil {
+				c.Logger().Errorf("pickup qr: %v", err)
+			}
+			out.QRError = code
+		}
+		return writeJSON(c, http.StatusOK, out)
 	}
 }
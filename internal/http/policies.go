@@ -0,0 +1,171 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// CreatePolicy — регистрация политики выпуска
+// @Summary     Создать политику
+// @Description Политика задаёт разрешённые зоны (вместе с потомками), max_ttl_s вместо глобального MAX_TTL_H, one_time по умолчанию, обязательные attrs, level и список X-Actor, которым разрешён выпуск.
+// @Tags        policies
+// @Accept      json
+// @Produce     json
+// @Param       request body dto.CreatePolicyRequest true "Policy"
+// @Success     201 {object} dto.PolicyResponse
+// @Failure     400 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /policies [post]
+func CreatePolicy(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req dto.CreatePolicyRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		p, err := svc.CreatePolicy(c.Request().Context(), req.ToPolicy())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusCreated, dto.FromPolicy(p))
+	}
+}
+
+// ListPolicies — политики организации
+// @Summary     Список политик
+// @Tags        policies
+// @Produce     json
+// @Param       org_id query string false "Org ID"
+// @Success     200 {object} dto.ListPoliciesResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /policies [get]
+func ListPolicies(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		orgID, err := dto.ParseListPoliciesQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		ps, err := svc.ListPolicies(c.Request().Context(), orgID)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromPolicies(ps))
+	}
+}
+
+// GetPolicy — карточка политики
+// @Summary     Получить политику
+// @Tags        policies
+// @Produce     json
+// @Param       id     path  string true "Policy ID"
+// @Param       org_id query string true "Org ID"
+// @Success     200 {object} dto.PolicyResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /policies/{id} [get]
+func GetPolicy(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		orgID, err := dto.ParseOrgQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		p, err := svc.GetPolicy(c.Request().Context(), orgID, id)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromPolicy(p))
+	}
+}
+
+// UpdatePolicy — замена правил политики
+// @Summary     Изменить политику
+// @Description Полностью заменяет правила; id и организация неизменны, выпущенные пропуска не пересматриваются.
+// @Tags        policies
+// @Accept      json
+// @Produce     json
+// @Param       id      path  string                  true "Policy ID"
+// @Param       org_id  query string                  true "Org ID"
+// @Param       request body  dto.UpdatePolicyRequest true "Policy"
+// @Success     200 {object} dto.PolicyResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /policies/{id} [put]
+func UpdatePolicy(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		orgID, err := dto.ParseOrgQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		var req dto.UpdatePolicyRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		p, err := svc.UpdatePolicy(c.Request().Context(), req.ToPolicy(orgID, id))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromPolicy(p))
+	}
+}
+
+// DeletePolicy — удаление политики
+// @Summary     Удалить политику
+// @Description Только политику без Active/Suspended пропусков.
+// @Tags        policies
+// @Param       id     path  string true "Policy ID"
+// @Param       org_id query string true "Org ID"
+// @Success     204
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /policies/{id} [delete]
+func DeletePolicy(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		orgID, err := dto.ParseOrgQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		if err := svc.DeletePolicy(c.Request().Context(), orgID, id); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
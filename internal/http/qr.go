@@ -0,0 +1,70 @@
+package http
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/qr"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// PassQR — QR-код выданного JWS пропуска
+// @Summary     QR-код пропуска
+// @Description Compact JWS пропуска (как payload в GET /passes/{id}) в виде QR-кода. format — png (по умолчанию) или svg, size — сторона в пикселях (64..2048, по умолчанию 256), ecc — уровень коррекции L, M (по умолчанию), Q или H. Если JWS не помещается в QR-код на выбранном уровне — 422 qr_too_large.
+// @Tags        passes
+// @Produce     image/png
+// @Produce     image/svg+xml
+// @Produce     json
+// @Param       id      path  string true  "Pass ID"
+// @Param       format  query string false "png|svg"
+// @Param       size    query int    false "Сторона в пикселях"
+// @Param       ecc     query string false "L|M|Q|H"
+// @Success     200 {file} file "QR-код"
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     422 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /passes/{id}/qr [get]
+func PassQR(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, ok := passIDParam(c)
+		if !ok {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		opts, err := qr.ParseOptions(c.QueryParam("format"), c.QueryParam("size"), c.QueryParam("ecc"))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		v, err := svc.GetPass(c.Request().Context(), id)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		img, err := qr.Render(v.Payload, opts)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		return c.Blob(http.StatusOK, opts.ContentType(), img)
+	}
+}
+
+// qrDataURI — QR-код payload как data URI для ответа pickup. При любой ошибке вместо
+// изображения возвращается её код как в MapError; err — только для непредвиденных ошибок,
+// которые стоит записать в журнал (payload не поместился — ожидаемый исход, err=nil)
+func qrDataURI(payload string, opts qr.Options) (uri, errCode string, err error) {
+	img, err := qr.Render(payload, opts)
+	if err != nil {
+		_, apiErr := MapError(err)
+		if errors.Is(err, qr.ErrTooLarge) {
+			return "", apiErr.Code, nil
+		}
+		return "", apiErr.Code, err
+	}
+	return "data:" + opts.ContentType() + ";base64," + base64.StdEncoding.EncodeToString(img), "", nil
+}
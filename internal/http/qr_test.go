@@ -0,0 +1,63 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Неверные параметры QR-кода отклоняются до обращения к сервису, поэтому он не нужен
+func TestQROptions400(t *testing.T) {
+	const passID = "3f1c2a9e-6b7d-4c1e-9a55-0d2b8e7f6a10"
+	tests := []struct {
+		name    string
+		query   string
+		body    string
+		wantMsg string
+	}{
+		{name: "qr format", query: "format=gif", wantMsg: "qr format must be png or svg"},
+		{name: "qr size below min", query: "size=10", wantMsg: "qr size must be from 64 to 2048"},
+		{name: "qr size above max", query: "size=4096", wantMsg: "qr size must be from 64 to 2048"},
+		{name: "qr size not a number", query: "size=big", wantMsg: "qr size must be from 64 to 2048"},
+		{name: "qr ecc", query: "ecc=Z", wantMsg: "qr ecc must be one of L, M, Q, H"},
+		{name: "pickup format", body: `{"token":"T","qr":{"format":"bmp"}}`, wantMsg: "qr format must be png or svg"},
+		{name: "pickup size", body: `{"token":"T","qr":{"size":5000}}`, wantMsg: "qr size must be from 64 to 2048"},
+		{name: "pickup negative size", body: `{"token":"T","qr":{"size":-1}}`, wantMsg: "qr size must be from 64 to 2048"},
+		{name: "pickup ecc", body: `{"token":"T","qr":{"ecc":"low"}}`, wantMsg: "qr ecc must be one of L, M, Q, H"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			var err error
+			if tt.body == "" {
+				req := httptest.NewRequest(http.MethodGet, "/passes/"+passID+"/qr?"+tt.query, nil)
+				c := e.NewContext(req, rec)
+				c.SetParamNames("id")
+				c.SetParamValues(passID)
+				err = PassQR(nil)(c)
+			} else {
+				req := httptest.NewRequest(http.MethodPost, "/pickup", strings.NewReader(tt.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				err = Pickup(nil)(e.NewContext(req, rec))
+			}
+			if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			var got APIError
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Code != "invalid_request" || got.Message != tt.wantMsg {
+				t.Fatalf("body = %+v, want invalid_request %q", got, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// RevocationList — подписанный список отозванных пропусков для офлайн-контроллеров
+// @Summary     Список отзывов (CRL)
+// @Description payload — compact JWS (активный ключ эмитента) с отозванными, но ещё не истёкшими пропусками.
+// @Description since=0 — полный список; since=N — дельта событий после версии N. version монотонно растёт.
+// @Tags        revocations
+// @Produce     json
+// @Param       org_id query string false "Org ID (без него — по всем организациям)"
+// @Param       since  query int    false "Версия, полученная ранее"
+// @Success     200 {object} dto.RevocationListResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Failure     503 {object} APIError
+// @Router      /revocations [get]
+func RevocationList(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		orgID, since, err := dto.ParseRevocationQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.GetRevocationList(c.Request().Context(), orgID, since)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromRevocationList(res))
+	}
+}
+
+// StatusList — подписанная битовая строка статусов (StatusList2021-style)
+// @Summary     Список статусов (bitstring)
+// @Description payload — compact JWS с base64url(gzip(bits)); бит pass.status.index = 1 — пропуск отозван или приостановлен (после reinstate бит снова 0).
+// @Description Адрес совпадает с pass.status.list в payload пропуска (STATUS_LIST_URL).
+// @Tags        revocations
+// @Produce     json
+// @Success     200 {object} dto.StatusListResponse
+// @Failure     500 {object} APIError
+// @Failure     503 {object} APIError
+// @Router      /status-list [get]
+func StatusList(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		res, err := svc.GetStatusList(c.Request().Context())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromStatusList(res))
+	}
+}
@@ -7,11 +7,10 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vbncursed/vkr/issue-service/internal/config"
-	"github.com/vbncursed/vkr/issue-service/internal/repo"
 	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
 )
 
-func Router(pool *pgxpool.Pool, cfg config.Config) *echo.Echo {
+func Router(pool *pgxpool.Pool, svc *issvc.Service, cfg config.Config) *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
 	e.HidePort = true
@@ -30,13 +29,43 @@ func Router(pool *pgxpool.Pool, cfg config.Config) *echo.Echo {
 	v1.GET("/healthz", Healthz)
 	v1.GET("/readyz", Readyz(pool))
 
-	// Business endpoints (DI): создаём сервис один раз
-	store := repo.NewStore(pool)
-	svc := issvc.New(store, store, issvc.RealClock{}, issvc.JWSSigner{})
-	v1.POST("/passes", CreatePass(svc, cfg))
+	// Business endpoints (DI): сервис создаётся один раз в main
+	v1.POST("/passes", CreatePass(svc))
+	v1.GET("/passes", ListPasses(svc))
+	v1.POST("/passes\\:revoke", BulkRevokePasses(svc))
+	v1.POST("/passes\\:batch", BatchCreatePasses(svc))
+	v1.POST("/passes\\:import", ImportPasses(svc))
+	v1.GET("/passes\\:export", ExportPasses(svc))
+	v1.GET("/passes/:id", GetPass(svc))
+	v1.GET("/passes/:id/qr", PassQR(svc))
 	v1.POST("/passes/:id/revoke", RevokePass(svc))
+	v1.PATCH("/passes/:id", AmendPass(svc))
+	v1.POST("/passes/:id/renew", RenewPass(svc))
+	v1.POST("/passes/:id/suspend", SuspendPass(svc))
+	v1.POST("/passes/:id/reinstate", ReinstatePass(svc))
+	v1.POST("/passes/:id/redeem", RedeemPass(svc))
 	v1.POST("/passes/:id/approve", ApprovePass(svc, cfg))
 	v1.POST("/pickup", Pickup(svc))
+	v1.POST("/verify", Verify(svc, cfg))
+	v1.GET("/revocations", RevocationList(svc))
+	v1.GET("/status-list", StatusList(svc))
+
+	// Zones
+	v1.POST("/zones", CreateZone(svc))
+	v1.GET("/zones", ListZones(svc))
+	v1.GET("/zones/:id", GetZone(svc))
+	v1.PATCH("/zones/:id", UpdateZone(svc))
+	v1.DELETE("/zones/:id", DeleteZone(svc))
+
+	// Policies
+	v1.POST("/policies", CreatePolicy(svc))
+	v1.GET("/policies", ListPolicies(svc))
+	v1.GET("/policies/:id", GetPolicy(svc))
+	v1.PUT("/policies/:id", UpdatePolicy(svc))
+	v1.DELETE("/policies/:id", DeletePolicy(svc))
+
+	// Admin
+	v1.POST("/admin/keys/:kid/compromise", CompromiseKey(svc))
 
 	// JWKS
 	e.GET("/.well-known/keys", JWKS(svc))
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/config"
+	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// Verify — авторитетная проверка пропуска по compact JWS
+// @Summary     Проверить пропуск
+// @Description Проверяет подпись по issuer_keys (active/retired), статус в БД и окно nbf/exp с допуском VERIFY_SKEW_S.
+// @Description Невалидный пропуск — это 200 с valid=false и кодом reason.
+// @Tags        verify
+// @Accept      json
+// @Produce     json
+// @Param       request body dto.VerifyRequest true "Verify"
+// @Success     200 {object} dto.VerifyResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /verify [post]
+func Verify(svc *issvc.Service, cfg config.Config) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req dto.VerifyRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		res, err := svc.VerifyPass(c.Request().Context(), strings.TrimSpace(req.Payload), cfg.ClockSkew)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromVerifyResult(res))
+	}
+}
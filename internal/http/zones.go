@@ -0,0 +1,172 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vbncursed/vkr/issue-service/internal/http/dto"
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// CreateZone — регистрация зоны
+// @Summary     Создать зону
+// @Description Иерархия building > floor > room: у здания нет parent_id, у этажа родитель — здание, у комнаты — этаж той же организации.
+// @Tags        zones
+// @Accept      json
+// @Produce     json
+// @Param       request body dto.CreateZoneRequest true "Zone"
+// @Success     201 {object} dto.ZoneResponse
+// @Failure     400 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /zones [post]
+func CreateZone(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req dto.CreateZoneRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		z, err := svc.CreateZone(c.Request().Context(), req.ToZone())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusCreated, dto.FromZone(z))
+	}
+}
+
+// ListZones — зоны организации
+// @Summary     Список зон
+// @Tags        zones
+// @Produce     json
+// @Param       org_id    query string false "Org ID"
+// @Param       parent_id query string false "Только прямые потомки зоны"
+// @Success     200 {object} dto.ListZonesResponse
+// @Failure     400 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /zones [get]
+func ListZones(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		orgID, parentID, err := dto.ParseListZonesQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		zs, err := svc.ListZones(c.Request().Context(), orgID, parentID)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromZones(zs))
+	}
+}
+
+// GetZone — карточка зоны
+// @Summary     Получить зону
+// @Tags        zones
+// @Produce     json
+// @Param       id     path  string true "Zone ID"
+// @Param       org_id query string true "Org ID"
+// @Success     200 {object} dto.ZoneResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /zones/{id} [get]
+func GetZone(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		orgID, err := dto.ParseOrgQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		z, err := svc.GetZone(c.Request().Context(), orgID, id)
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromZone(z))
+	}
+}
+
+// UpdateZone — переименование или перенос зоны
+// @Summary     Изменить зону
+// @Description Меняет name и/или parent_id; уровень и организация зоны неизменны.
+// @Tags        zones
+// @Accept      json
+// @Produce     json
+// @Param       id      path  string                true "Zone ID"
+// @Param       org_id  query string                true "Org ID"
+// @Param       request body  dto.UpdateZoneRequest true "Changes"
+// @Success     200 {object} dto.ZoneResponse
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /zones/{id} [patch]
+func UpdateZone(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		orgID, err := dto.ParseOrgQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		var req dto.UpdateZoneRequest
+		if err := c.Bind(&req); err != nil {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "malformed"})
+		}
+		if err := req.Validate(); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		z, err := svc.UpdateZone(c.Request().Context(), req.ToCommand(orgID, id))
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return writeJSON(c, http.StatusOK, dto.FromZone(z))
+	}
+}
+
+// DeleteZone — удаление зоны
+// @Summary     Удалить зону
+// @Description Только зону без дочерних зон, без Active/Suspended пропусков и не перечисленную в allowed_zones политик (иначе 409 zone_in_use).
+// @Tags        zones
+// @Param       id     path  string true "Zone ID"
+// @Param       org_id query string true "Org ID"
+// @Success     204
+// @Failure     400 {object} APIError
+// @Failure     404 {object} APIError
+// @Failure     409 {object} APIError
+// @Failure     500 {object} APIError
+// @Router      /zones/{id} [delete]
+func DeleteZone(svc *issvc.Service) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return writeJSON(c, http.StatusBadRequest, APIError{Code: "invalid_request", Message: "id"})
+		}
+		orgID, err := dto.ParseOrgQuery(c.QueryParams())
+		if err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		if err := svc.DeleteZone(c.Request().Context(), orgID, id); err != nil {
+			status, apiErr := MapError(err)
+			return writeJSON(c, status, apiErr)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
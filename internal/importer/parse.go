@@ -0,0 +1,353 @@
+// Package importer — выпуск пропусков по списку посетителей в CSV: разбор файла в команды
+// выпуска, выпуск пакетами и файл результата с id пропусков и pickup-токенами.
+// Используется эндпоинтом POST /passes:import и утилитой cmd/import-passes.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+var (
+	ErrInvalidFile  = errors.New("invalid_import_file")
+	ErrInvalidField = errors.New("invalid_import_field")
+)
+
+// MaxFileSize и MaxRows — пределы размера импортируемого файла
+const (
+	MaxFileSize = 10 << 20
+	MaxRows     = 5000
+)
+
+// FileError — файл не разбирается целиком; Line — строка файла, 0 — файл в целом
+type FileError struct {
+	Line   int
+	Reason string
+}
+
+func (e *FileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+	}
+	return e.Reason
+}
+
+func (e *FileError) Unwrap() error { return ErrInvalidFile }
+
+// FieldError — значение колонки строки не разбирается; без Column — строка в целом
+type FieldError struct {
+	Column string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.Column == "" {
+		return e.Reason
+	}
+	return e.Column + ": " + e.Reason
+}
+
+func (e *FieldError) Unwrap() error { return ErrInvalidField }
+
+// Options — умолчания для строк файла
+type Options struct {
+	// OrgID — организация строк без колонки org_id или с пустым значением
+	OrgID string
+	// Location — пояс для дат без смещения; nil — UTC
+	Location *time.Location
+	// Actor — инициатор выпуска, сверяется с issuers политик
+	Actor string
+	// Pickup — выдать каждому выпущенному пропуску pickup-токен
+	Pickup bool
+	// PickupTTL — срок pickup-токена, но не дольше exp пропуска; 0 — DefaultPickupTTL
+	PickupTTL time.Duration
+}
+
+// Row — строка файла с номером строки: команда выпуска или ошибка разбора
+type Row struct {
+	Line int
+	Cmd  issvc.IssuePassCommand
+	Err  error
+}
+
+// File — разобранный файл; Comma — разделитель, им же пишется файл результата
+type File struct {
+	Comma rune
+	Rows  []Row
+}
+
+// колонки файла; attrs — JSON-объект, attr.<имя> — строковый атрибут
+const (
+	colSubjectName = "subject_name"
+	colZone        = "zone"
+	colNbf         = "nbf"
+	colExp         = "exp"
+	colPolicy      = "policy"
+	colOrgID       = "org_id"
+	colMaxUses     = "max_uses"
+	colOneTime     = "one_time"
+	colAttrs       = "attrs"
+	attrPrefix     = "attr."
+)
+
+var requiredColumns = []string{colSubjectName, colZone, colNbf, colExp, colPolicy}
+
+// columnAliases — принятые альтернативные имена колонок
+var columnAliases = map[string]string{
+	"zone_id":   colZone,
+	"zones":     colZone,
+	"zone_ids":  colZone,
+	"policy_id": colPolicy,
+}
+
+// Parse читает CSV с заголовком. Разделитель — ',' или ';' (как сохраняет Excel в русской
+// локали), определяется по заголовку; UTF-8 BOM пропускается, пустые строки тоже.
+// Ошибки заголовка и структуры файла возвращаются FileError, ошибки значений — в Row.Err.
+// Поддерживается только CSV: книга XLSX (zip-архив) отклоняется с подсказкой сохранить её как CSV.
+func Parse(r io.Reader, opts Options) (*File, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFileSize {
+		return nil, &FileError{Reason: fmt.Sprintf("file larger than %d bytes", MaxFileSize)}
+	}
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return nil, &FileError{Line: 1, Reason: "XLSX is not supported; save the sheet as CSV UTF-8"}
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	comma := detectComma(data)
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &FileError{Reason: "empty file"}
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+	cols, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	f := &File{Comma: comma}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		line, _ := cr.FieldPos(0)
+		if blank(rec) {
+			continue
+		}
+		if len(f.Rows) == MaxRows {
+			return nil, &FileError{Line: line, Reason: fmt.Sprintf("more than %d rows", MaxRows)}
+		}
+		row := Row{Line: line}
+		if len(rec) != len(cols) {
+			row.Err = &FieldError{Reason: fmt.Sprintf("expected %d fields, got %d", len(cols), len(rec))}
+		} else {
+			row.Cmd, row.Err = parseRow(cols, rec, opts, loc)
+		}
+		f.Rows = append(f.Rows, row)
+	}
+	if len(f.Rows) == 0 {
+		return nil, &FileError{Reason: "no rows"}
+	}
+	return f, nil
+}
+
+// detectComma — ';', если в первой строке их больше, чем запятых
+func detectComma(data []byte) rune {
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func csvError(err error) error {
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		return &FileError{Line: pe.Line, Reason: pe.Err.Error()}
+	}
+	return err
+}
+
+// parseHeader — имена колонок в нижнем регистре с учётом синонимов; неизвестная колонка —
+// ошибка, чтобы опечатка в заголовке не теряла данные молча
+func parseHeader(header []string) ([]string, error) {
+	cols := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if alias, ok := columnAliases[name]; ok {
+			name = alias
+		}
+		switch name {
+		case colSubjectName, colZone, colNbf, colExp, colPolicy, colOrgID, colMaxUses, colOneTime, colAttrs:
+		default:
+			if !strings.HasPrefix(name, attrPrefix) || len(name) == len(attrPrefix) {
+				return nil, &FileError{Line: 1, Reason: fmt.Sprintf("unknown column %q; attribute columns are named attr.<name>", h)}
+			}
+			// имя атрибута сохраняет регистр
+			name = attrPrefix + strings.TrimSpace(h)[len(attrPrefix):]
+		}
+		if seen[name] {
+			return nil, &FileError{Line: 1, Reason: fmt.Sprintf("duplicate column %q", h)}
+		}
+		seen[name] = true
+		cols[i] = name
+	}
+	var missing []string
+	for _, c := range requiredColumns {
+		if !seen[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &FileError{Line: 1, Reason: "missing columns: " + strings.Join(missing, ", ")}
+	}
+	return cols, nil
+}
+
+// parseRow — команда выпуска из значений строки; проверки самой команды — IssuePassCommand.Validate
+func parseRow(cols, rec []string, opts Options, loc *time.Location) (issvc.IssuePassCommand, error) {
+	req := issvc.IssuePassCommand{OrgID: opts.OrgID, Attrs: map[string]any{}, Actor: opts.Actor}
+	for i, col := range cols {
+		v := strings.TrimSpace(rec[i])
+		switch col {
+		case colSubjectName:
+			req.SubjectName = v
+		case colZone:
+			// несколько зон — через пробел, ',' или ';'; повторы отбрасываются, первая — основная
+			for _, z := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+				if !slices.Contains(req.ZoneIDs, z) {
+					req.ZoneIDs = append(req.ZoneIDs, z)
+				}
+			}
+		case colPolicy:
+			req.PolicyID = v
+		case colOrgID:
+			if v != "" {
+				req.OrgID = v
+			}
+		case colNbf:
+			t, err := parseTime(v, loc, false)
+			if err != nil {
+				return req, &FieldError{Column: col, Reason: err.Error()}
+			}
+			req.NBF = t
+		case colExp:
+			t, err := parseTime(v, loc, true)
+			if err != nil {
+				return req, &FieldError{Column: col, Reason: err.Error()}
+			}
+			req.EXP = t
+		case colMaxUses:
+			if v == "" {
+				continue
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return req, &FieldError{Column: col, Reason: "must be an integer"}
+			}
+			req.MaxUses = n
+		case colOneTime:
+			if v == "" {
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return req, &FieldError{Column: col, Reason: "must be true or false"}
+			}
+			req.OneTime = &b
+		case colAttrs:
+			if v == "" {
+				continue
+			}
+			var attrs map[string]any
+			if err := json.Unmarshal([]byte(v), &attrs); err != nil || attrs == nil {
+				return req, &FieldError{Column: col, Reason: "must be a JSON object"}
+			}
+			for k, a := range attrs {
+				if _, ok := req.Attrs[k]; !ok {
+					req.Attrs[k] = a
+				}
+			}
+		default:
+			// attr.<имя>; пустая ячейка — атрибут не задан
+			if v != "" {
+				req.Attrs[col[len(attrPrefix):]] = v
+			}
+		}
+	}
+	return req, nil
+}
+
+// timeLayouts — форматы дат без смещения: ISO и как их показывает Excel в русской локали
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+}
+
+var dateLayouts = []string{time.DateOnly, "02.01.2006"}
+
+// parseTime — RFC3339 или местное время в loc. Дата без времени — начало суток, а для exp
+// (endOfDay) — конец: пропуск на день визита действует весь этот день.
+func parseTime(v string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, errors.New("required")
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			if endOfDay {
+				t = t.AddDate(0, 0, 1)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q; use RFC3339, YYYY-MM-DD HH:MM or DD.MM.YYYY HH:MM", v)
+}
+
+func blank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
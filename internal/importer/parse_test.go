@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testOrg = "00000000-0000-0000-0000-000000000001"
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantErr  string
+		wantLine int
+	}{
+		{name: "required columns", in: "subject_name,zone,nbf,exp,policy\nA,z1,2026-03-02,2026-03-02,p\n"},
+		{name: "aliases and case", in: "Subject_Name,Zone_ID,NBF,EXP,Policy_ID\nA,z1,2026-03-02,2026-03-02,p\n"},
+		{name: "optional columns", in: "subject_name,zone,nbf,exp,policy,org_id,max_uses,one_time,attrs,attr.Car\nA,z1,2026-03-02,2026-03-02,p,,,,,\n"},
+		{name: "missing columns", in: "subject_name,zone,nbf\nA,z1,2026-03-02\n", wantErr: "missing columns: exp, policy", wantLine: 1},
+		{name: "unknown column", in: "subject_name,zone,nbf,exp,policy,phone\nA,z1,2026-03-02,2026-03-02,p,1\n", wantErr: `unknown column "phone"`, wantLine: 1},
+		{name: "empty attr name", in: "subject_name,zone,nbf,exp,policy,attr.\nA,z1,2026-03-02,2026-03-02,p,1\n", wantErr: `unknown column "attr."`, wantLine: 1},
+		{name: "duplicate via alias", in: "subject_name,zone,zone_id,nbf,exp,policy\nA,z1,z2,2026-03-02,2026-03-02,p\n", wantErr: `duplicate column "zone_id"`, wantLine: 1},
+		{name: "empty file", in: "", wantErr: "empty file"},
+		{name: "header only", in: "subject_name,zone,nbf,exp,policy\n", wantErr: "no rows"},
+		{name: "xlsx", in: "PK\x03\x04rest-of-zip", wantErr: "XLSX is not supported", wantLine: 1},
+		{name: "broken quotes", in: "subject_name,zone,nbf,exp,policy\nA,z1,2026-03-02,2026-03-02,p\n\"B,z1,2026-03-02,2026-03-02,p\n", wantErr: "extraneous or missing", wantLine: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(strings.NewReader(tt.in), Options{OrgID: testOrg})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if f.Rows[0].Err != nil {
+					t.Fatalf("row error = %v", f.Rows[0].Err)
+				}
+				return
+			}
+			var fe *FileError
+			if !errors.As(err, &fe) || !errors.Is(err, ErrInvalidFile) {
+				t.Fatalf("Parse() error = %v, want FileError", err)
+			}
+			if !strings.Contains(fe.Reason, tt.wantErr) {
+				t.Fatalf("Parse() reason = %q, want %q", fe.Reason, tt.wantErr)
+			}
+			if fe.Line != tt.wantLine {
+				t.Fatalf("Parse() line = %d, want %d", fe.Line, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestParseRows(t *testing.T) {
+	in := "\xef\xbb\xbfsubject_name;zone;nbf;exp;policy;max_uses;attrs;attr.car\n" +
+		"Иван Иванов;lobby floor-3;2026-03-02 09:00;02.03.2026;visitors;2;\"{\"\"shift\"\":\"\"day\"\"}\";А123ВС\n" +
+		"\n" +
+		"Пётр;lobby;2026-03-02;2026-03-02\n" +
+		"Анна;lobby;2026-03-02;2026-03-02;visitors;;;;extra\n" +
+		"Олег;lobby;вчера;2026-03-02;visitors;;;\n" +
+		"\"Мария\nСидорова\";lobby;2026-03-02;2026-03-02;visitors;x;;\n" +
+		"Ольга;lobby;2026-03-02;2026-03-02;visitors;;[1];\n" +
+		"Кира;lobby;2026-03-02;2026-03-02;visitors;;\"{\"\"car\"\":\"\"json\"\",\"\"n\"\":1}\";из колонки\n"
+
+	msk, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Parse(strings.NewReader(in), Options{OrgID: testOrg, Location: msk, Actor: "desk"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if f.Comma != ';' {
+		t.Fatalf("Comma = %q, want ';'", f.Comma)
+	}
+
+	type want struct {
+		line   int
+		column string // колонка FieldError; "-" — ошибка строки в целом, "" — без ошибки
+	}
+	wants := []want{
+		{line: 2},
+		// пустая строка 3 пропускается, но нумерация строк файла сохраняется
+		{line: 4, column: "-"},
+		{line: 5, column: "-"},
+		{line: 6, column: colNbf},
+		// многострочное значение — номер строки, где запись начинается
+		{line: 7, column: colMaxUses},
+		{line: 9, column: colAttrs},
+		{line: 10},
+	}
+	if len(f.Rows) != len(wants) {
+		t.Fatalf("rows = %d, want %d", len(f.Rows), len(wants))
+	}
+	for i, w := range wants {
+		r := f.Rows[i]
+		if r.Line != w.line {
+			t.Errorf("row %d: line = %d, want %d", i, r.Line, w.line)
+		}
+		switch w.column {
+		case "":
+			if r.Err != nil {
+				t.Errorf("line %d: error = %v", r.Line, r.Err)
+			}
+		default:
+			var fe *FieldError
+			if !errors.As(r.Err, &fe) || !errors.Is(r.Err, ErrInvalidField) {
+				t.Errorf("line %d: error = %v, want FieldError", r.Line, r.Err)
+				continue
+			}
+			if col := fe.Column; (w.column == "-" && col != "") || (w.column != "-" && col != w.column) {
+				t.Errorf("line %d: column = %q, want %q", r.Line, col, w.column)
+			}
+		}
+	}
+
+	cmd := f.Rows[0].Cmd
+	if cmd.OrgID != testOrg || cmd.Actor != "desk" || cmd.PolicyID != "visitors" || cmd.SubjectName != "Иван Иванов" {
+		t.Errorf("cmd = %+v", cmd)
+	}
+	if !reflect.DeepEqual(cmd.ZoneIDs, []string{"lobby", "floor-3"}) {
+		t.Errorf("ZoneIDs = %v", cmd.ZoneIDs)
+	}
+	if want := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC); !cmd.NBF.Equal(want) {
+		t.Errorf("NBF = %s, want %s", cmd.NBF, want)
+	}
+	// дата без времени в exp — конец этого дня по Москве
+	if want := time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC); !cmd.EXP.Equal(want) {
+		t.Errorf("EXP = %s, want %s", cmd.EXP, want)
+	}
+	if cmd.MaxUses != 2 {
+		t.Errorf("MaxUses = %d, want 2", cmd.MaxUses)
+	}
+	if want := map[string]any{"shift": "day", "car": "А123ВС"}; !reflect.DeepEqual(cmd.Attrs, want) {
+		t.Errorf("Attrs = %v, want %v", cmd.Attrs, want)
+	}
+	// attr.<имя> важнее одноимённого ключа из attrs
+	if want := map[string]any{"car": "из колонки", "n": float64(1)}; !reflect.DeepEqual(f.Rows[6].Cmd.Attrs, want) {
+		t.Errorf("Attrs = %v, want %v", f.Rows[6].Cmd.Attrs, want)
+	}
+}
+
+func TestParseRowOrgColumn(t *testing.T) {
+	in := "subject_name,zone,nbf,exp,policy,org_id\n" +
+		"A,z1,2026-03-02,2026-03-02,p,00000000-0000-0000-0000-000000000002\n" +
+		"B,z1,2026-03-02,2026-03-02,p,\n"
+	f, err := Parse(strings.NewReader(in), Options{OrgID: testOrg})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := f.Rows[0].Cmd.OrgID; got != "00000000-0000-0000-0000-000000000002" {
+		t.Errorf("org_id column: OrgID = %s", got)
+	}
+	if got := f.Rows[1].Cmd.OrgID; got != testOrg {
+		t.Errorf("empty org_id: OrgID = %s, want %s", got, testOrg)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		in        string
+		loc       *time.Location
+		endOfDay  bool
+		want      time.Time
+		wantError bool
+	}{
+		{name: "rfc3339 ignores loc", in: "2026-03-02T09:00:00+05:00", loc: msk, want: time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC)},
+		{name: "iso local", in: "2026-03-02T09:00", loc: msk, want: time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)},
+		{name: "iso local with seconds", in: "2026-03-02 09:00:30", loc: msk, want: time.Date(2026, 3, 2, 6, 0, 30, 0, time.UTC)},
+		{name: "russian local", in: "02.03.2026 09:00", loc: msk, want: time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)},
+		{name: "utc location", in: "2026-03-02 09:00", loc: time.UTC, want: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{name: "date is start of day", in: "2026-03-02", loc: msk, want: time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC)},
+		{name: "date as exp is end of day", in: "02.03.2026", loc: msk, endOfDay: true, want: time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC)},
+		{name: "datetime as exp is exact", in: "2026-03-02 18:00", loc: msk, endOfDay: true, want: time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)},
+		{name: "empty", in: "", loc: msk, wantError: true},
+		{name: "no such day", in: "2026-02-30", loc: msk, wantError: true},
+		{name: "no such hour", in: "2026-03-02 25:00", loc: msk, wantError: true},
+		{name: "us order", in: "03/02/2026", loc: msk, wantError: true},
+		{name: "bad offset", in: "2026-03-02T09:00:00+25:00", loc: msk, wantError: true},
+		{name: "words", in: "tomorrow", loc: msk, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTime(tt.in, tt.loc, tt.endOfDay)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("parseTime(%q) error = %v, wantError %v", tt.in, err, tt.wantError)
+			}
+			if err == nil && !got.Equal(tt.want) {
+				t.Fatalf("parseTime(%q) = %s, want %s", tt.in, got.UTC(), tt.want)
+			}
+		})
+	}
+}
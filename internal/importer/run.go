@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+	"github.com/vbncursed/vkr/issue-service/internal/util"
+)
+
+// Issuer — сценарии сервиса, нужные импорту
+type Issuer interface {
+	IssuePassBatch(ctx context.Context, cmds []issvc.IssuePassCommand, allOrNothing bool) ([]issvc.IssueBatchItem, error)
+	ApprovePass(ctx context.Context, id string, ttl time.Duration) (issvc.ApproveResult, error)
+}
+
+// BatchSize — сколько строк выпускается одним пакетом, как предел POST /passes:batch
+const BatchSize = 500
+
+// DefaultPickupTTL и MaxPickupTTL — срок pickup-токенов импорта по умолчанию и его предел
+const (
+	DefaultPickupTTL = 24 * time.Hour
+	MaxPickupTTL     = 7 * 24 * time.Hour
+)
+
+// Result — итог строки файла. PassID заполнен, если пропуск выпущен; Err — ошибка строки
+// или, при выпущенном пропуске, ошибка выдачи pickup-токена.
+type Result struct {
+	Line          int
+	SubjectName   string
+	PassID        string
+	PickupToken   string
+	PickupExpires string
+	Err           error
+}
+
+// Run выпускает пропуска по строкам файла пакетами по BatchSize. Строки проверяются
+// как POST /passes (IssuePassCommand.Validate), ошибка строки не мешает остальным. Ошибка ключа или записи пакета
+// прерывает импорт: уже выпущенные пакеты остаются, строки пакета и все следующие получают
+// эту ошибку, и она же возвращается вместе с результатом.
+func Run(ctx context.Context, iss Issuer, f *File, opts Options, now time.Time) ([]Result, error) {
+	pickupTTL := opts.PickupTTL
+	if pickupTTL <= 0 {
+		pickupTTL = DefaultPickupTTL
+	}
+	results := make([]Result, len(f.Rows))
+	cmds := make([]issvc.IssuePassCommand, 0, BatchSize)
+	pos := make([]int, 0, BatchSize)
+	flush := func() error {
+		if len(cmds) == 0 {
+			return nil
+		}
+		items, err := iss.IssuePassBatch(ctx, cmds, false)
+		if err != nil {
+			for _, i := range pos {
+				results[i].Err = err
+			}
+			return err
+		}
+		for j, it := range items {
+			r := &results[pos[j]]
+			if it.Err != nil {
+				r.Err = it.Err
+				continue
+			}
+			r.PassID = it.Result.ID
+			if opts.Pickup {
+				// токен не переживает пропуск
+				res, err := iss.ApprovePass(ctx, r.PassID, min(pickupTTL, cmds[j].EXP.Sub(now)))
+				if err != nil {
+					r.Err = err
+					continue
+				}
+				r.PickupToken, r.PickupExpires = res.Token, res.ExpiresAt
+			}
+		}
+		cmds, pos = cmds[:0], pos[:0]
+		return nil
+	}
+
+	var failed error
+	for i, row := range f.Rows {
+		results[i] = Result{Line: row.Line, SubjectName: row.Cmd.SubjectName, Err: row.Err}
+		if row.Err != nil {
+			continue
+		}
+		if failed != nil {
+			results[i].Err = failed
+			continue
+		}
+		if err := row.Cmd.Validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		cmds = append(cmds, row.Cmd)
+		pos = append(pos, i)
+		if len(cmds) == BatchSize {
+			failed = flush()
+		}
+	}
+	if failed != nil {
+		return results, failed
+	}
+	return results, flush()
+}
+
+// Describe — код и текст ошибки строки для файла результата
+type Describe func(err error) (code, message string)
+
+// resultHeader — колонки файла результата
+var resultHeader = []string{"line", "subject_name", "status", "pass_id", "pickup_token", "pickup_expires_at", "error_code", "error_message"}
+
+// WriteResult пишет файл результата CSV с разделителем comma: по строке на строку исходного
+// файла, status — issued или failed. UTF-8 BOM в начале — чтобы Excel открыл кириллицу.
+func WriteResult(w io.Writer, results []Result, comma rune, describe Describe) error {
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(resultHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		status := "issued"
+		if r.PassID == "" {
+			status = "failed"
+		}
+		var code, message string
+		if r.Err != nil {
+			code, message = describe(r.Err)
+		}
+		if err := cw.Write([]string{
+			strconv.Itoa(r.Line), util.SpreadsheetSafe(r.SubjectName), status, r.PassID, r.PickupToken, r.PickupExpires, code, util.SpreadsheetSafe(message),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Counts — число выпущенных и невыпущенных строк
+func Counts(results []Result) (issued, failed int) {
+	for _, r := range results {
+		if r.PassID != "" {
+			issued++
+		} else {
+			failed++
+		}
+	}
+	return issued, failed
+}
@@ -0,0 +1,2 @@
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
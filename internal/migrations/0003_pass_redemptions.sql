@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS pass_redemptions (
+  id BIGSERIAL PRIMARY KEY,
+  pass_id UUID NOT NULL REFERENCES passes(id) ON DELETE CASCADE,
+  reader_id TEXT NOT NULL,
+  zone_id TEXT NOT NULL,
+  redeemed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_redemptions_pass ON pass_redemptions(pass_id);
@@ -0,0 +1,6 @@
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS max_uses INT CHECK (max_uses IS NULL OR max_uses > 0);
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS uses INT NOT NULL DEFAULT 0;
+
+-- счётчик для уже погашенных пропусков и лимит 1 для одноразовых
+UPDATE passes p SET uses = (SELECT count(*) FROM pass_redemptions r WHERE r.pass_id = p.id);
+UPDATE passes SET max_uses = 1 WHERE one_time AND max_uses IS NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS revocation_events (
+  seq BIGSERIAL PRIMARY KEY,
+  pass_id UUID NOT NULL REFERENCES passes(id) ON DELETE CASCADE,
+  org_id UUID NOT NULL,
+  exp TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revocation_events_org ON revocation_events(org_id, seq);
+CREATE INDEX IF NOT EXISTS idx_revocation_events_exp ON revocation_events(exp);
+
+-- события для пропусков, отозванных до появления журнала
+INSERT INTO revocation_events (pass_id, org_id, exp, revoked_at)
+SELECT id, org_id, exp, COALESCE(revoked_at, created_at) FROM passes
+WHERE status = 'Revoked'
+ORDER BY COALESCE(revoked_at, created_at);
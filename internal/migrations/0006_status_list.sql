@@ -0,0 +1,5 @@
+CREATE SEQUENCE IF NOT EXISTS pass_status_index_seq MINVALUE 0 START 0;
+
+-- индекс в битовой строке статусов; у пропусков, выпущенных раньше, его нет
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS status_index BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_passes_status_index ON passes(status_index);
@@ -0,0 +1,6 @@
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS revocation_reason TEXT
+  CHECK (revocation_reason IN ('lost','compromised','policy_change','superseded','other'));
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS revocation_note TEXT;
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS revoked_by TEXT;
+
+ALTER TABLE revocation_events ADD COLUMN IF NOT EXISTS reason TEXT;
@@ -0,0 +1,6 @@
+ALTER TABLE passes DROP CONSTRAINT IF EXISTS passes_status_check;
+ALTER TABLE passes ADD CONSTRAINT passes_status_check
+  CHECK (status IN ('Active','Suspended','Revoked','Expired'));
+
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS suspended_by TEXT;
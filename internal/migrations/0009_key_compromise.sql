@@ -0,0 +1,7 @@
+ALTER TABLE issuer_keys DROP CONSTRAINT IF EXISTS issuer_keys_status_check;
+ALTER TABLE issuer_keys ADD CONSTRAINT issuer_keys_status_check
+  CHECK (status IN ('active','retired','compromised'));
+ALTER TABLE issuer_keys ADD COLUMN IF NOT EXISTS compromised_at TIMESTAMPTZ;
+
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS replaces_id UUID REFERENCES passes(id);
+CREATE INDEX IF NOT EXISTS idx_passes_issuer_key ON passes(issuer_key_id);
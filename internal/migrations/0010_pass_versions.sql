@@ -0,0 +1,15 @@
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS pass_versions (
+  pass_id UUID NOT NULL REFERENCES passes(id) ON DELETE CASCADE,
+  version INT NOT NULL,
+  zone_id TEXT NOT NULL,
+  subject_name TEXT NOT NULL,
+  issuer_key_id TEXT NOT NULL REFERENCES issuer_keys(key_id),
+  status_index BIGINT,
+  signature BYTEA NOT NULL,
+  payload BYTEA NOT NULL,
+  superseded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  superseded_by TEXT,
+  PRIMARY KEY (pass_id, version)
+);
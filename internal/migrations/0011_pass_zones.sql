@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS pass_zones (
+  pass_id UUID NOT NULL REFERENCES passes(id) ON DELETE CASCADE,
+  zone_id TEXT NOT NULL,
+  PRIMARY KEY (pass_id, zone_id)
+);
+CREATE INDEX IF NOT EXISTS idx_pass_zones_zone ON pass_zones(zone_id);
+
+INSERT INTO pass_zones (pass_id, zone_id)
+SELECT id, zone_id FROM passes
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE pass_versions ADD COLUMN IF NOT EXISTS zone_ids TEXT[];
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS zones (
+  id TEXT PRIMARY KEY,
+  org_id UUID NOT NULL,
+  name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('building','floor','room')),
+  parent_id TEXT REFERENCES zones(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_zones_org ON zones(org_id);
+CREATE INDEX IF NOT EXISTS idx_zones_parent ON zones(parent_id);
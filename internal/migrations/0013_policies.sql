@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS policies (
+  id TEXT PRIMARY KEY,
+  org_id UUID NOT NULL,
+  name TEXT NOT NULL,
+  level TEXT NOT NULL DEFAULT '',
+  allowed_zones TEXT[] NOT NULL DEFAULT '{}',
+  max_ttl_s BIGINT CHECK (max_ttl_s > 0),
+  default_one_time BOOLEAN NOT NULL DEFAULT FALSE,
+  required_attrs TEXT[] NOT NULL DEFAULT '{}',
+  issuers TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_policies_org ON policies(org_id);
+CREATE INDEX IF NOT EXISTS idx_passes_policy ON passes(policy_id);
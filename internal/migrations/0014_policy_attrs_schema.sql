@@ -0,0 +1 @@
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS attrs_schema JSONB;
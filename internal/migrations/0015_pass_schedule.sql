@@ -0,0 +1 @@
+ALTER TABLE passes ADD COLUMN IF NOT EXISTS schedule JSONB;
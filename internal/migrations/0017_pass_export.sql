@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_passes_org_created ON passes(org_id, created_at, id);
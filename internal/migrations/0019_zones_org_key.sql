@@ -0,0 +1,9 @@
+ALTER TABLE zones DROP CONSTRAINT IF EXISTS zones_parent_id_fkey;
+ALTER TABLE zones DROP CONSTRAINT IF EXISTS zones_pkey;
+ALTER TABLE zones ADD CONSTRAINT zones_pkey PRIMARY KEY (org_id, id);
+ALTER TABLE zones ADD CONSTRAINT zones_parent_id_fkey
+  FOREIGN KEY (org_id, parent_id) REFERENCES zones(org_id, id);
+
+DROP INDEX IF EXISTS idx_zones_org;
+DROP INDEX IF EXISTS idx_zones_parent;
+CREATE INDEX IF NOT EXISTS idx_zones_parent ON zones(org_id, parent_id);
@@ -0,0 +1,6 @@
+ALTER TABLE policies DROP CONSTRAINT IF EXISTS policies_pkey;
+ALTER TABLE policies ADD CONSTRAINT policies_pkey PRIMARY KEY (org_id, id);
+
+DROP INDEX IF EXISTS idx_policies_org;
+DROP INDEX IF EXISTS idx_passes_policy;
+CREATE INDEX IF NOT EXISTS idx_passes_policy ON passes(org_id, policy_id);
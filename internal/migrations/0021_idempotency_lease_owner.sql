@@ -0,0 +1 @@
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS lease_owner TEXT NOT NULL DEFAULT '';
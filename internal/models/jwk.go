@@ -0,0 +1,15 @@
+package models
+
+// JWK — публичный ключ эмитента в формате /.well-known/keys (OKP Ed25519)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	X   string `json:"x"`
+}
+
+// JWKSet — набор публичных ключей эмитента
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
@@ -4,14 +4,18 @@ import "time"
 
 type PayloadPass struct {
 	ID         string         `json:"id"`
+	Version    int            `json:"version,omitempty"`
 	Type       string         `json:"type"`
 	Level      string         `json:"level"`
 	Scopes     []string       `json:"scopes"`
 	OneTime    bool           `json:"one_time"`
+	MaxUses    int            `json:"max_uses,omitempty"`
 	NBF        time.Time      `json:"nbf"`
 	EXP        time.Time      `json:"exp"`
+	Schedule   *Schedule      `json:"schedule,omitempty"`
 	Attrs      map[string]any `json:"attrs"`
 	HolderHint string         `json:"holder_hint"`
+	Status     *PayloadStatus `json:"status,omitempty"`
 }
 
 type PayloadMeta struct {
@@ -21,6 +25,7 @@ type PayloadMeta struct {
 	IssuedAt      time.Time `json:"issued_at"`
 	Nonce         []byte    `json:"nonce"`
 	SchemaVersion int       `json:"schema_version"`
+	Replaces      string    `json:"replaces,omitempty"`
 }
 
 type SignedPayload struct {
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// RevocationReason — причина отзыва пропуска
+type RevocationReason string
+
+const (
+	RevocationLost         RevocationReason = "lost"
+	RevocationCompromised  RevocationReason = "compromised"
+	RevocationPolicyChange RevocationReason = "policy_change"
+	RevocationSuperseded   RevocationReason = "superseded"
+	RevocationOther        RevocationReason = "other"
+)
+
+// Valid — причина из допустимого набора
+func (r RevocationReason) Valid() bool {
+	switch r {
+	case RevocationLost, RevocationCompromised, RevocationPolicyChange, RevocationSuperseded, RevocationOther:
+		return true
+	}
+	return false
+}
+
+type RevocationEntry struct {
+	PassID    string           `json:"id"`
+	EXP       time.Time        `json:"exp"`
+	RevokedAt time.Time        `json:"revoked_at"`
+	Reason    RevocationReason `json:"reason,omitempty"`
+	Seq       int64            `json:"seq"`
+}
+
+// RevocationList — подписываемый список отозванных, но ещё не истёкших пропусков.
+// Since=0 — полный список, иначе дельта событий с seq > Since.
+type RevocationList struct {
+	V           int               `json:"v"`
+	Type        string            `json:"type"`
+	OrgID       string            `json:"org_id,omitempty"`
+	Version     int64             `json:"version"`
+	Since       int64             `json:"since"`
+	IssuedAt    time.Time         `json:"issued_at"`
+	Entries     []RevocationEntry `json:"entries"`
+	IssuerKeyID string            `json:"issuer_key_id"`
+}
+
+const RevocationListType = "revocation_list"
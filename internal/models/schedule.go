@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	// база часовых поясов встраивается: считыватели и образы без tzdata тоже проверяют расписания
+	_ "time/tzdata"
+)
+
+// ErrInvalidSchedule — расписание не разбирается или противоречиво
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// MaxScheduleWindows и MaxScheduleExceptions — пределы размера расписания в payload
+const (
+	MaxScheduleWindows    = 16
+	MaxScheduleExceptions = 366
+)
+
+// Schedule — повторяющиеся окна доступа внутри nbf/exp: дни недели (mon..sun), интервалы
+// времени HH:MM в часовом поясе TZ и даты-исключения YYYY-MM-DD, в которые окна не открываются.
+// Интервал с end не позже start переходит через полночь и относится к дню своего начала.
+type Schedule struct {
+	TZ         string           `json:"tz"`
+	Weekdays   []string         `json:"weekdays"`
+	Windows    []ScheduleWindow `json:"windows"`
+	Exceptions []string         `json:"exceptions,omitempty"`
+}
+
+// ScheduleWindow — интервал [start, end) в местном времени; end "24:00" — до конца суток
+type ScheduleWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// Validate проверяет пояс, дни, интервалы и даты; пустые дни или интервалы недопустимы
+func (s *Schedule) Validate() error {
+	// пустой пояс — UTC, Local — пояс сервера; оба неявны, требуем имя IANA
+	if _, err := time.LoadLocation(s.TZ); err != nil || s.TZ == "" || s.TZ == "Local" {
+		return fmt.Errorf("%w: unknown tz %q", ErrInvalidSchedule, s.TZ)
+	}
+	if len(s.Weekdays) == 0 {
+		return fmt.Errorf("%w: weekdays required", ErrInvalidSchedule)
+	}
+	for _, d := range s.Weekdays {
+		if _, ok := weekdayNames[d]; !ok {
+			return fmt.Errorf("%w: weekday %q must be one of mon..sun", ErrInvalidSchedule, d)
+		}
+	}
+	if len(s.Windows) == 0 || len(s.Windows) > MaxScheduleWindows {
+		return fmt.Errorf("%w: from 1 to %d windows required", ErrInvalidSchedule, MaxScheduleWindows)
+	}
+	for _, w := range s.Windows {
+		start, err := clockMinutes(w.Start)
+		if err != nil || start == 24*60 {
+			return fmt.Errorf("%w: window start %q must be HH:MM", ErrInvalidSchedule, w.Start)
+		}
+		end, err := clockMinutes(w.End)
+		if err != nil {
+			return fmt.Errorf("%w: window end %q must be HH:MM", ErrInvalidSchedule, w.End)
+		}
+		if start == end {
+			return fmt.Errorf("%w: window %s-%s is empty", ErrInvalidSchedule, w.Start, w.End)
+		}
+	}
+	if len(s.Exceptions) > MaxScheduleExceptions {
+		return fmt.Errorf("%w: at most %d exceptions", ErrInvalidSchedule, MaxScheduleExceptions)
+	}
+	for _, d := range s.Exceptions {
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			return fmt.Errorf("%w: exception %q must be YYYY-MM-DD", ErrInvalidSchedule, d)
+		}
+	}
+	return nil
+}
+
+// Allows сообщает, попадает ли момент t в одно из окон; nil-расписание не ограничивает.
+// Неразборчивое расписание не открывает ни одного окна.
+func (s *Schedule) Allows(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	loc, err := time.LoadLocation(s.TZ)
+	if err != nil {
+		return false
+	}
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	yesterday := local.AddDate(0, 0, -1)
+	for _, w := range s.Windows {
+		start, err1 := clockMinutes(w.Start)
+		end, err2 := clockMinutes(w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if start < end {
+			if now >= start && now < end && s.opensOn(local) {
+				return true
+			}
+			continue
+		}
+		// через полночь: вечерняя часть — сегодняшнее окно, утренняя — вчерашнее
+		if now >= start && s.opensOn(local) {
+			return true
+		}
+		if now < end && s.opensOn(yesterday) {
+			return true
+		}
+	}
+	return false
+}
+
+// opensOn — окна открываются в этот местный день: день недели выбран и дата не исключена
+func (s *Schedule) opensOn(day time.Time) bool {
+	if slices.Contains(s.Exceptions, day.Format(time.DateOnly)) {
+		return false
+	}
+	for _, d := range s.Weekdays {
+		if weekdayNames[d] == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// clockMinutes — минуты от полуночи для строго HH:MM (две цифры, двоеточие, две цифры),
+// 24:00 допустимо
+func clockMinutes(v string) (int, error) {
+	if len(v) != 5 || v[2] != ':' {
+		return 0, ErrInvalidSchedule
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if v[i] < '0' || v[i] > '9' {
+			return 0, ErrInvalidSchedule
+		}
+	}
+	h := int(v[0]-'0')*10 + int(v[1]-'0')
+	m := int(v[3]-'0')*10 + int(v[4]-'0')
+	if m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, ErrInvalidSchedule
+	}
+	return h*60 + m, nil
+}
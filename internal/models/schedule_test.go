@@ -0,0 +1,138 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClockMinutes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{in: "00:00", want: 0},
+		{in: "09:30", want: 570},
+		{in: "23:59", want: 1439},
+		{in: "24:00", want: 1440},
+		{in: "24:01", wantErr: true},
+		{in: "25:00", wantErr: true},
+		{in: "12:60", wantErr: true},
+		{in: "9:00", wantErr: true},
+		{in: "+9:00", wantErr: true},
+		{in: "-1:00", wantErr: true},
+		{in: "09:+5", wantErr: true},
+		{in: " 9:00", wantErr: true},
+		{in: "09-00", wantErr: true},
+		{in: "0900", wantErr: true},
+		{in: "09:00:00", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "ab:cd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := clockMinutes(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clockMinutes(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Fatalf("clockMinutes(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func validSchedule() Schedule {
+	return Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "18:00"}}}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(s *Schedule)
+		wantErr bool
+	}{
+		{name: "valid"},
+		{name: "overnight window", mutate: func(s *Schedule) { s.Windows = []ScheduleWindow{{Start: "22:00", End: "06:00"}} }},
+		{name: "until end of day", mutate: func(s *Schedule) { s.Windows = []ScheduleWindow{{Start: "18:00", End: "24:00"}} }},
+		{name: "exceptions", mutate: func(s *Schedule) { s.Exceptions = []string{"2026-03-09"} }},
+		{name: "empty tz", mutate: func(s *Schedule) { s.TZ = "" }, wantErr: true},
+		{name: "local tz", mutate: func(s *Schedule) { s.TZ = "Local" }, wantErr: true},
+		{name: "unknown tz", mutate: func(s *Schedule) { s.TZ = "Mars/Olympus" }, wantErr: true},
+		{name: "no weekdays", mutate: func(s *Schedule) { s.Weekdays = nil }, wantErr: true},
+		{name: "bad weekday", mutate: func(s *Schedule) { s.Weekdays = []string{"monday"} }, wantErr: true},
+		{name: "no windows", mutate: func(s *Schedule) { s.Windows = nil }, wantErr: true},
+		{name: "too many windows", mutate: func(s *Schedule) {
+			s.Windows = make([]ScheduleWindow, MaxScheduleWindows+1)
+			for i := range s.Windows {
+				s.Windows[i] = ScheduleWindow{Start: "09:00", End: "10:00"}
+			}
+		}, wantErr: true},
+		{name: "start 24:00", mutate: func(s *Schedule) { s.Windows = []ScheduleWindow{{Start: "24:00", End: "06:00"}} }, wantErr: true},
+		{name: "empty window", mutate: func(s *Schedule) { s.Windows = []ScheduleWindow{{Start: "09:00", End: "09:00"}} }, wantErr: true},
+		{name: "signed start", mutate: func(s *Schedule) { s.Windows = []ScheduleWindow{{Start: "+9:00", End: "18:00"}} }, wantErr: true},
+		{name: "single digit end", mutate: func(s *Schedule) { s.Windows = []ScheduleWindow{{Start: "09:00", End: "9:30"}} }, wantErr: true},
+		{name: "bad exception", mutate: func(s *Schedule) { s.Exceptions = []string{"09.03.2026"} }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validSchedule()
+			if tt.mutate != nil {
+				tt.mutate(&s)
+			}
+			err := s.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSchedule) {
+				t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidSchedule)
+			}
+		})
+	}
+}
+
+func TestScheduleAllows(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	// понедельник 2 марта 2026 года по Москве
+	mon := func(h, m int) time.Time { return time.Date(2026, 3, 2, h, m, 0, 0, msk) }
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		at       time.Time
+		want     bool
+	}{
+		{name: "nil schedule", at: mon(3, 0), want: true},
+		{name: "inside window", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "18:00"}}}, at: mon(9, 0), want: true},
+		{name: "end is exclusive", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "18:00"}}}, at: mon(18, 0)},
+		{name: "before window", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "18:00"}}}, at: mon(8, 59)},
+		{name: "other weekday", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"tue"}, Windows: []ScheduleWindow{{Start: "09:00", End: "18:00"}}}, at: mon(12, 0)},
+		{name: "second window", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "08:00", End: "09:00"}, {Start: "13:00", End: "14:00"}}}, at: mon(13, 30), want: true},
+		{name: "until end of day", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "18:00", End: "24:00"}}}, at: mon(23, 59), want: true},
+		{name: "exception date", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "18:00"}}, Exceptions: []string{"2026-03-02"}}, at: mon(12, 0)},
+
+		// окно через полночь относится к дню начала
+		{name: "overnight evening part", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "22:00", End: "06:00"}}}, at: mon(23, 0), want: true},
+		{name: "overnight morning part of previous day", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"sun"}, Windows: []ScheduleWindow{{Start: "22:00", End: "06:00"}}}, at: mon(5, 59), want: true},
+		{name: "overnight morning part not opened", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "22:00", End: "06:00"}}}, at: mon(5, 0)},
+		{name: "overnight after end", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"sun"}, Windows: []ScheduleWindow{{Start: "22:00", End: "06:00"}}}, at: mon(6, 0)},
+		// неделя переходит через воскресенье в понедельник
+		{name: "overnight across week end", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"sun"}, Windows: []ScheduleWindow{{Start: "23:00", End: "01:00"}}}, at: mon(0, 30), want: true},
+		{name: "overnight previous day excepted", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"sun"}, Windows: []ScheduleWindow{{Start: "22:00", End: "06:00"}}, Exceptions: []string{"2026-03-01"}}, at: mon(1, 0)},
+
+		// день и время берутся в поясе расписания, а не момента проверки
+		{name: "tz shifts weekday", schedule: &Schedule{TZ: "Asia/Tokyo", Weekdays: []string{"tue"}, Windows: []ScheduleWindow{{Start: "04:00", End: "05:00"}}}, at: mon(22, 30), want: true},
+		{name: "tz shifts clock", schedule: &Schedule{TZ: "UTC", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "10:00"}}}, at: mon(9, 30)},
+		{name: "tz from utc instant", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "09:00", End: "10:00"}}}, at: time.Date(2026, 3, 2, 6, 30, 0, 0, time.UTC), want: true},
+		{name: "unknown tz", schedule: &Schedule{TZ: "Mars/Olympus", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "00:00", End: "24:00"}}}, at: mon(12, 0)},
+		{name: "malformed window ignored", schedule: &Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []ScheduleWindow{{Start: "+9:00", End: "18:00"}}}, at: mon(12, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Allows(tt.at); got != tt.want {
+				t.Fatalf("Allows(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
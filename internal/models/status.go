@@ -1,10 +1,12 @@
 package models
 
-// PassStatus — доменный статус пропуска
+// PassStatus — доменный статус пропуска.
+// Active ⇄ Suspended — обратимая блокировка; Revoked и Expired терминальны.
 type PassStatus string
 
 const (
-	StatusActive  PassStatus = "Active"
-	StatusRevoked PassStatus = "Revoked"
-	StatusExpired PassStatus = "Expired"
+	StatusActive    PassStatus = "Active"
+	StatusSuspended PassStatus = "Suspended"
+	StatusRevoked   PassStatus = "Revoked"
+	StatusExpired   PassStatus = "Expired"
 )
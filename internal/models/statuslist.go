@@ -0,0 +1,107 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	StatusListType          = "status_list"
+	StatusPurposeRevocation = "revocation"
+	// StatusListMinBits — минимальный размер списка (16 KiB), чтобы индекс не выдавал пропуск
+	StatusListMinBits = 131072
+	// statusListMaxBytes ограничивает распакованный список при разборе
+	statusListMaxBytes = 1 << 24
+)
+
+var (
+	ErrStatusIndexOutOfRange = errors.New("status index out of range")
+	ErrStatusListTooLarge    = errors.New("status list too large")
+)
+
+// PayloadStatus — ссылка из пропуска на его бит в списке статусов
+type PayloadStatus struct {
+	Purpose string `json:"purpose"`
+	List    string `json:"list"`
+	Index   int64  `json:"index"`
+}
+
+// StatusList — подписываемый список статусов (в духе W3C StatusList2021).
+// EncodedList — base64url(gzip(bits)), бит 0 — старший бит первого байта; 1 — отозван или приостановлен.
+type StatusList struct {
+	V           int       `json:"v"`
+	Type        string    `json:"type"`
+	ID          string    `json:"id"`
+	Purpose     string    `json:"purpose"`
+	Size        int64     `json:"size"`
+	EncodedList string    `json:"encoded_list"`
+	IssuedAt    time.Time `json:"issued_at"`
+	IssuerKeyID string    `json:"issuer_key_id"`
+}
+
+// NewStatusBits — битовая строка, вмещающая maxIndex, не короче StatusListMinBits
+func NewStatusBits(maxIndex int64) []byte {
+	size := int64(StatusListMinBits)
+	for size <= maxIndex {
+		size *= 2
+	}
+	return make([]byte, size/8)
+}
+
+// SetStatusBit выставляет бит index
+func SetStatusBit(bits []byte, index int64) error {
+	if index < 0 || index/8 >= int64(len(bits)) {
+		return ErrStatusIndexOutOfRange
+	}
+	bits[index/8] |= 0x80 >> (index % 8)
+	return nil
+}
+
+// StatusBit читает бит index
+func StatusBit(bits []byte, index int64) (bool, error) {
+	if index < 0 || index/8 >= int64(len(bits)) {
+		return false, ErrStatusIndexOutOfRange
+	}
+	return bits[index/8]&(0x80>>(index%8)) != 0, nil
+}
+
+// EncodeStatusBits сжимает и кодирует битовую строку для StatusList.EncodedList
+func EncodeStatusBits(bits []byte) (string, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := zw.Write(bits); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeStatusBits — обратное к EncodeStatusBits
+func DecodeStatusBits(encoded string) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	bits, err := io.ReadAll(io.LimitReader(zr, statusListMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(bits) > statusListMaxBytes {
+		return nil, ErrStatusListTooLarge
+	}
+	return bits, nil
+}
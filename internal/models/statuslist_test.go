@@ -0,0 +1,117 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStatusBitsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxIndex int64
+		set      []int64
+		clear    []int64
+		// байты в начале списка после установки: бит 0 — старший бит первого байта
+		wantHead []byte
+	}{
+		{name: "empty", wantHead: []byte{0x00, 0x00}},
+		{name: "bit 0 is msb", set: []int64{0}, wantHead: []byte{0x80, 0x00}},
+		{name: "bit 7 is lsb", set: []int64{7}, wantHead: []byte{0x01, 0x00}},
+		{name: "bit 8 starts second byte", set: []int64{8}, wantHead: []byte{0x00, 0x80}},
+		{name: "several bits", set: []int64{0, 5, 9, 15}, wantHead: []byte{0x84, 0x41}},
+		{name: "cleared bit", set: []int64{1, 2, 3}, clear: []int64{2}, wantHead: []byte{0x50, 0x00}},
+		{name: "last bit of min list", set: []int64{StatusListMinBits - 1}, wantHead: []byte{0x00, 0x00}},
+		{name: "grown list", maxIndex: StatusListMinBits, set: []int64{StatusListMinBits}, wantHead: []byte{0x00, 0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bits := NewStatusBits(tt.maxIndex)
+			for _, i := range tt.set {
+				if err := SetStatusBit(bits, i); err != nil {
+					t.Fatalf("SetStatusBit(%d) error = %v", i, err)
+				}
+			}
+			for _, i := range tt.clear {
+				bits[i/8] &^= 0x80 >> (i % 8)
+			}
+			if !bytes.Equal(bits[:len(tt.wantHead)], tt.wantHead) {
+				t.Fatalf("bits head = %08b, want %08b", bits[:len(tt.wantHead)], tt.wantHead)
+			}
+
+			enc, err := EncodeStatusBits(bits)
+			if err != nil {
+				t.Fatalf("EncodeStatusBits() error = %v", err)
+			}
+			got, err := DecodeStatusBits(enc)
+			if err != nil {
+				t.Fatalf("DecodeStatusBits() error = %v", err)
+			}
+			if !bytes.Equal(got, bits) {
+				t.Fatal("DecodeStatusBits() differs from encoded bits")
+			}
+			for i := int64(0); i < int64(len(got))*8; i++ {
+				on, err := StatusBit(got, i)
+				if err != nil {
+					t.Fatalf("StatusBit(%d) error = %v", i, err)
+				}
+				want := slices.Contains(tt.set, i) && !slices.Contains(tt.clear, i)
+				if on != want {
+					t.Fatalf("StatusBit(%d) = %v, want %v", i, on, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewStatusBitsSize(t *testing.T) {
+	tests := []struct {
+		maxIndex int64
+		wantBits int64
+	}{
+		{maxIndex: 0, wantBits: StatusListMinBits},
+		{maxIndex: StatusListMinBits - 1, wantBits: StatusListMinBits},
+		{maxIndex: StatusListMinBits, wantBits: 2 * StatusListMinBits},
+		{maxIndex: 3 * StatusListMinBits, wantBits: 4 * StatusListMinBits},
+	}
+	for _, tt := range tests {
+		if got := int64(len(NewStatusBits(tt.maxIndex))) * 8; got != tt.wantBits {
+			t.Errorf("NewStatusBits(%d) = %d bits, want %d", tt.maxIndex, got, tt.wantBits)
+		}
+	}
+}
+
+func TestStatusBitOutOfRange(t *testing.T) {
+	bits := NewStatusBits(0)
+	for _, i := range []int64{-1, StatusListMinBits} {
+		if err := SetStatusBit(bits, i); !errors.Is(err, ErrStatusIndexOutOfRange) {
+			t.Errorf("SetStatusBit(%d) error = %v, want %v", i, err, ErrStatusIndexOutOfRange)
+		}
+		if _, err := StatusBit(bits, i); !errors.Is(err, ErrStatusIndexOutOfRange) {
+			t.Errorf("StatusBit(%d) error = %v, want %v", i, err, ErrStatusIndexOutOfRange)
+		}
+	}
+}
+
+func TestDecodeStatusBitsRejects(t *testing.T) {
+	var big bytes.Buffer
+	zw := gzip.NewWriter(&big)
+	if _, err := zw.Write(make([]byte, statusListMaxBytes+1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeStatusBits(base64.RawURLEncoding.EncodeToString(big.Bytes())); !errors.Is(err, ErrStatusListTooLarge) {
+		t.Fatalf("oversized list: error = %v, want %v", err, ErrStatusListTooLarge)
+	}
+	if _, err := DecodeStatusBits("not base64!"); err == nil {
+		t.Fatal("bad base64: want error")
+	}
+	if _, err := DecodeStatusBits(base64.RawURLEncoding.EncodeToString([]byte("plain"))); err == nil {
+		t.Fatal("not gzip: want error")
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+// ZoneKind — уровень зоны в иерархии building > floor > room
+type ZoneKind string
+
+const (
+	ZoneBuilding ZoneKind = "building"
+	ZoneFloor    ZoneKind = "floor"
+	ZoneRoom     ZoneKind = "room"
+)
+
+// Valid — уровень из допустимого набора
+func (k ZoneKind) Valid() bool {
+	switch k {
+	case ZoneBuilding, ZoneFloor, ZoneRoom:
+		return true
+	}
+	return false
+}
+
+// ParentKind — уровень родителя; у здания родителя нет
+func (k ZoneKind) ParentKind() ZoneKind {
+	switch k {
+	case ZoneFloor:
+		return ZoneBuilding
+	case ZoneRoom:
+		return ZoneFloor
+	}
+	return ""
+}
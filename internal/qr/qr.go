@@ -0,0 +1,130 @@
+// Package qr — QR-код compact JWS пропуска в PNG или SVG. Уровень коррекции и размер задаются
+// запросом, чтобы клиенты не подбирали их каждый по-своему.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid qr format")
+	ErrInvalidLevel  = errors.New("invalid qr ecc level")
+	ErrInvalidSize   = errors.New("invalid qr size")
+	ErrTooLarge      = errors.New("payload too large for qr")
+)
+
+// Форматы изображения
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Пределы и умолчания размера стороны изображения в пикселях
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// Options — формат, сторона в пикселях и уровень коррекции L, M, Q или H
+type Options struct {
+	Format string
+	Size   int
+	Level  string
+}
+
+// levels — уровни коррекции ISO/IEC 18004 в терминах go-qrcode
+var levels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// ParseOptions — параметры с умолчаниями: png, DefaultSize, уровень M
+func ParseOptions(format, size, level string) (Options, error) {
+	o := Options{
+		Format: strings.ToLower(strings.TrimSpace(format)),
+		Size:   DefaultSize,
+		Level:  strings.ToUpper(strings.TrimSpace(level)),
+	}
+	switch o.Format {
+	case "":
+		o.Format = FormatPNG
+	case FormatPNG, FormatSVG:
+	default:
+		return Options{}, ErrInvalidFormat
+	}
+	if o.Level == "" {
+		o.Level = "M"
+	}
+	if _, ok := levels[o.Level]; !ok {
+		return Options{}, ErrInvalidLevel
+	}
+	if size = strings.TrimSpace(size); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			return Options{}, ErrInvalidSize
+		}
+		o.Size = n
+	}
+	if o.Size < MinSize || o.Size > MaxSize {
+		return Options{}, ErrInvalidSize
+	}
+	return o, nil
+}
+
+// ContentType — MIME-тип изображения формата o.Format
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render — QR-код content в формате o; не помещается на этом уровне коррекции — ErrTooLarge
+func Render(content string, o Options) ([]byte, error) {
+	level, ok := levels[o.Level]
+	if !ok {
+		return nil, ErrInvalidLevel
+	}
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		// go-qrcode возвращает единственную ошибку кодирования — "content too long to encode"
+		return nil, fmt.Errorf("%w: %d bytes at level %s", ErrTooLarge, len(content), o.Level)
+	}
+	if o.Format == FormatSVG {
+		return svg(code.Bitmap(), o.Size), nil
+	}
+	return code.PNG(o.Size)
+}
+
+// svg — векторный QR-код из матрицы модулей (с полем тишины): тёмные модули строки
+// сливаются в один прямоугольник, одна единица viewBox — один модуль
+func svg(bitmap [][]bool, size int) []byte {
+	n := len(bitmap)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", size, size, n, n)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n"+`<path fill="#000" d="`, n, n)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	b.WriteString("\"/>\n</svg>\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,78 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		size    string
+		level   string
+		want    Options
+		wantErr error
+	}{
+		{name: "defaults", want: Options{Format: FormatPNG, Size: DefaultSize, Level: "M"}},
+		{name: "svg", format: "svg", want: Options{Format: FormatSVG, Size: DefaultSize, Level: "M"}},
+		{name: "case and spaces", format: " SVG ", size: " 512 ", level: " q ", want: Options{Format: FormatSVG, Size: 512, Level: "Q"}},
+		{name: "min size", size: "64", want: Options{Format: FormatPNG, Size: MinSize, Level: "M"}},
+		{name: "max size", size: "2048", want: Options{Format: FormatPNG, Size: MaxSize, Level: "M"}},
+		{name: "level L", level: "L", want: Options{Format: FormatPNG, Size: DefaultSize, Level: "L"}},
+		{name: "level H", level: "h", want: Options{Format: FormatPNG, Size: DefaultSize, Level: "H"}},
+		{name: "unknown format", format: "jpeg", wantErr: ErrInvalidFormat},
+		{name: "size below min", size: "63", wantErr: ErrInvalidSize},
+		{name: "size above max", size: "2049", wantErr: ErrInvalidSize},
+		{name: "zero size", size: "0", wantErr: ErrInvalidSize},
+		{name: "negative size", size: "-256", wantErr: ErrInvalidSize},
+		{name: "size not a number", size: "256px", wantErr: ErrInvalidSize},
+		{name: "unknown level", level: "X", wantErr: ErrInvalidLevel},
+		{name: "level as word", level: "medium", wantErr: ErrInvalidLevel},
+		// формат проверяется первым
+		{name: "several errors", format: "gif", size: "1", level: "X", wantErr: ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.format, tt.size, tt.level)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	png, err := Render("payload", Options{Format: FormatPNG, Size: DefaultSize, Level: "M"})
+	if err != nil {
+		t.Fatalf("Render(png) error = %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Fatal("Render(png) is not a PNG")
+	}
+
+	o := Options{Format: FormatSVG, Size: 300, Level: "M"}
+	svg, err := Render("payload", o)
+	if err != nil {
+		t.Fatalf("Render(svg) error = %v", err)
+	}
+	if !bytes.Contains(svg, []byte(`width="300" height="300"`)) {
+		t.Fatalf("Render(svg) = %s", svg)
+	}
+	if o.ContentType() != "image/svg+xml" {
+		t.Fatalf("ContentType() = %s", o.ContentType())
+	}
+
+	// версия 40 на уровне H вмещает 1273 байта
+	if _, err := Render(strings.Repeat("a", 1500), Options{Format: FormatPNG, Size: DefaultSize, Level: "H"}); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Render(too large) error = %v, want %v", err, ErrTooLarge)
+	}
+	if _, err := Render("payload", Options{Format: FormatPNG, Size: DefaultSize, Level: "X"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Fatalf("Render(bad level) error = %v, want %v", err, ErrInvalidLevel)
+	}
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// whereBuilder собирает WHERE с позиционными параметрами; "?" в условии заменяется на $N
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+func (w *whereBuilder) add(cond string, args ...any) {
+	var b strings.Builder
+	i := 0
+	for _, r := range cond {
+		if r == '?' && i < len(args) {
+			w.args = append(w.args, args[i])
+			i++
+			b.WriteString("$" + strconv.Itoa(len(w.args)))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	w.conds = append(w.conds, b.String())
+}
+
+func (w *whereBuilder) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// escapeLike экранирует спецсимволы LIKE для поиска по префиксу
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// zoneMatch — пропуск действует в зоне: любая из его зон, а не только основная
+const zoneMatch = `EXISTS (SELECT 1 FROM ` + tablePassZones + ` z WHERE z.` + colPassID + ` = ` + tablePasses + `.` + colID + ` AND z.` + colZoneID + ` = ?)`
+
+// passFilterWhere переводит service.PassFilter в условия по таблице passes
+func passFilterWhere(f service.PassFilter) *whereBuilder {
+	w := &whereBuilder{}
+	if f.OrgID != "" {
+		w.add(colOrgID+`=?`, f.OrgID)
+	}
+	if f.PolicyID != "" {
+		w.add(colPolicyID+`=?`, f.PolicyID)
+	}
+	if f.ZoneID != "" {
+		w.add(zoneMatch, f.ZoneID)
+	}
+	if f.Status != "" {
+		w.add(colStatus+`=?`, f.Status)
+	}
+	if f.SubjectPrefix != "" {
+		w.add(colSubjectName+` LIKE ? ESCAPE '\'`, escapeLike(f.SubjectPrefix)+"%")
+	}
+	if f.NBFFrom != nil {
+		w.add(colNbf+`>=?`, *f.NBFFrom)
+	}
+	if f.NBFTo != nil {
+		w.add(colNbf+`<?`, *f.NBFTo)
+	}
+	if f.EXPFrom != nil {
+		w.add(colExp+`>=?`, *f.EXPFrom)
+	}
+	if f.EXPTo != nil {
+		w.add(colExp+`<?`, *f.EXPTo)
+	}
+	if f.IssuedFrom != nil {
+		w.add(colCreatedAt+`>=?`, *f.IssuedFrom)
+	}
+	if f.IssuedTo != nil {
+		w.add(colCreatedAt+`<?`, *f.IssuedTo)
+	}
+	return w
+}
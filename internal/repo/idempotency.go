@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// idemKeyWhere — условие на ключ в его пространстве: $1 org_id, $2 actor, $3 key
+const idemKeyWhere = colOrgID + `=$1 AND ` + colActor + `=$2 AND ` + colKey + `=$3`
+
+// ReserveIdempotencyKey — занимает ключ за owner под запрос с данным отпечатком на ttl. Истёкший
+// ключ и незавершённый дольше lease занимаются заново. Если ключ занят, возвращает его запись.
+func (s *Store) ReserveIdempotencyKey(ctx context.Context, key service.IdempotencyKey, owner string, requestHash []byte, now time.Time, ttl, lease time.Duration) (*service.IdempotencyRecord, error) {
+	reserve := `INSERT INTO ` + tableIdemKeys + ` (` + colOrgID + `, ` + colActor + `, ` + colKey + `, ` + colRequestHash + `, ` + colCreatedAt + `, ` + colExpiresAt + `, ` + colLeaseOwner + `) VALUES ($6,$7,$1,$2,$3,$4,$8)
+ON CONFLICT (` + colOrgID + `, ` + colActor + `, ` + colKey + `) DO UPDATE SET ` + colRequestHash + `=EXCLUDED.` + colRequestHash + `, ` + colResponse + `=NULL, ` +
+		colCreatedAt + `=EXCLUDED.` + colCreatedAt + `, ` + colExpiresAt + `=EXCLUDED.` + colExpiresAt + `, ` + colLeaseOwner + `=EXCLUDED.` + colLeaseOwner + `
+WHERE ` + tableIdemKeys + `.` + colExpiresAt + ` <= $3 OR (` + tableIdemKeys + `.` + colResponse + ` IS NULL AND ` + tableIdemKeys + `.` + colCreatedAt + ` <= $5)
+RETURNING ` + colKey
+	// между неудачной вставкой и чтением ключ могут освободить — тогда пробуем ещё раз
+	for range 2 {
+		var got string
+		err := s.pool.QueryRow(ctx, reserve, key.Key, requestHash, now, now.Add(ttl), now.Add(-lease), key.OrgID, key.Actor, owner).Scan(&got)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		rec := service.IdempotencyRecord{Key: key}
+		err = s.pool.QueryRow(ctx, `SELECT `+colRequestHash+`, `+colResponse+`, `+colCreatedAt+` FROM `+tableIdemKeys+` WHERE `+idemKeyWhere, key.OrgID, key.Actor, key.Key).
+			Scan(&rec.RequestHash, &rec.Response, &rec.CreatedAt)
+		if err == nil {
+			return &rec, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return nil, service.ErrIdemInProgress
+}
+
+// InsertPassCompletingKey — одной транзакцией сохраняет пропуск и ответ на запрос, если ключ всё ещё
+// занят за owner. Аренду перехватил другой запрос — ErrIdemInProgress, и пропуск не записывается.
+func (s *Store) InsertPassCompletingKey(ctx context.Context, key service.IdempotencyKey, owner string, p service.PassRecord, response []byte) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if err := insertPass(ctx, tx, p); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `UPDATE `+tableIdemKeys+` SET `+colResponse+`=$4 WHERE `+idemKeyWhere+` AND `+colLeaseOwner+`=$5 AND `+colResponse+` IS NULL`,
+		key.OrgID, key.Actor, key.Key, response, owner)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return service.ErrIdemInProgress
+	}
+	return tx.Commit(ctx)
+}
+
+// ReleaseIdempotencyKey — освобождает незавершённый ключ после неудачного запроса, если он ещё за owner
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, key service.IdempotencyKey, owner string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM `+tableIdemKeys+` WHERE `+idemKeyWhere+` AND `+colLeaseOwner+`=$4 AND `+colResponse+` IS NULL`,
+		key.OrgID, key.Actor, key.Key, owner)
+	return err
+}
+
+// PurgeIdempotencyKeys — удаляет ключи, чей срок хранения истёк
+func (s *Store) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM `+tableIdemKeys+` WHERE `+colExpiresAt+` <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
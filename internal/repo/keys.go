@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+	"github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// CompromiseIssuerKey — одной транзакцией помечает ключ compromised, при наличии fresh делает его
+// активным, отзывает все Active/Suspended пропуска ключа и сохраняет
+// замены, построенные replace (только для бывших Active)
+func (s *Store) CompromiseIssuerKey(ctx context.Context, kid string, fresh *service.NewIssuerKey, meta service.RevocationMeta, replace service.ReplaceFunc) ([]service.CompromisedPass, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT `+colStatus+` FROM `+tableIssuerKeys+` WHERE `+colKeyID+`=$1 FOR UPDATE`, kid).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, service.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	if status == "compromised" {
+		return nil, service.ErrConflict
+	}
+	if _, err := tx.Exec(ctx, `UPDATE `+tableIssuerKeys+` SET `+colStatus+`='compromised', `+colCompromised+`=now() WHERE `+colKeyID+`=$1`, kid); err != nil {
+		return nil, err
+	}
+	if fresh != nil {
+		if _, err := tx.Exec(ctx, `UPDATE `+tableIssuerKeys+` SET `+colStatus+`='retired' WHERE `+colStatus+`='active'`); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO `+tableIssuerKeys+` (`+colKeyID+`, `+colAlg+`, `+colPublicKey+`, `+colPrivateKey+`, `+colStatus+`) VALUES ($1,$2,$3,$4,'active')`,
+			fresh.KID, fresh.Alg, fresh.PublicKey, fresh.PrivateKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := lockRevocations(ctx, tx); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, `SELECT `+passViewColumns+` FROM `+tablePasses+` WHERE `+colIssuerKeyID+`=$1 AND `+colStatus+` = ANY($2) ORDER BY `+colID+` FOR UPDATE`,
+		kid, revocableStatuses)
+	if err != nil {
+		return nil, err
+	}
+	views, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (service.PassView, error) { return scanPassView(r) })
+	if err != nil {
+		return nil, err
+	}
+	out := make([]service.CompromisedPass, 0, len(views))
+	if len(views) == 0 {
+		return out, tx.Commit(ctx)
+	}
+
+	ids := make([]string, 0, len(views))
+	for _, v := range views {
+		ids = append(ids, v.ID)
+	}
+	cmd := `UPDATE ` + tablePasses + ` SET ` + revokeSet + ` WHERE ` + colID + ` = ANY($5::uuid[])`
+	if _, err := tx.Exec(ctx, cmd, string(im.StatusRevoked), string(meta.Reason), nullIfEmpty(meta.Note), nullIfEmpty(meta.Actor), ids); err != nil {
+		return nil, err
+	}
+	if err := insertRevocationEvents(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+
+	for _, v := range views {
+		entry := service.CompromisedPass{View: v}
+		if replace != nil && v.Status == string(im.StatusActive) {
+			rec, err := replace(v)
+			if err != nil {
+				return nil, err
+			}
+			if rec != nil {
+				if err := insertPass(ctx, tx, *rec); err != nil {
+					return nil, err
+				}
+				entry.Replacement = rec
+			}
+		}
+		out = append(out, entry)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
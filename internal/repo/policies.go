@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// policyColumns — колонки политики в порядке scanPolicy
+const policyColumns = colID + `, ` + colOrgID + `::text, ` + colName + `, ` + colLevel + `, ` + colAllowedZones + `, ` +
+	`COALESCE(` + colMaxTTLSec + `, 0), ` + colDefOneTime + `, ` + colRequiredAttr + `, ` + colAttrsSchema + `, ` + colIssuers + `, ` + colCreatedAt + `, ` + colUpdatedAt
+
+func scanPolicy(row pgx.Row) (service.Policy, error) {
+	var p service.Policy
+	var ttl int64
+	if err := row.Scan(&p.ID, &p.OrgID, &p.Name, &p.Level, &p.AllowedZones, &ttl, &p.DefaultOneTime,
+		&p.RequiredAttrs, &p.AttrsSchema, &p.Issuers, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return service.Policy{}, err
+	}
+	p.MaxTTL = time.Duration(ttl) * time.Second
+	return p, nil
+}
+
+// ttlSeconds — max_ttl_s колонки; 0 хранится как NULL (глобальный предел)
+func ttlSeconds(d time.Duration) *int64 {
+	if d <= 0 {
+		return nil
+	}
+	s := int64(d / time.Second)
+	return &s
+}
+
+// schemaOrNull — JSON схемы для jsonb; пустая схема хранится как NULL
+func schemaOrNull(raw json.RawMessage) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
+
+// nonNil — пустой массив вместо NULL для TEXT[] NOT NULL
+func nonNil(v []string) []string {
+	if v == nil {
+		return []string{}
+	}
+	return v
+}
+
+// InsertPolicy — новая политика; занятый id — ErrPolicyExists
+func (s *Store) InsertPolicy(ctx context.Context, p service.Policy) error {
+	_, err := s.pool.Exec(ctx, `INSERT INTO `+tablePolicies+` (`+colID+`, `+colOrgID+`, `+colName+`, `+colLevel+`, `+colAllowedZones+`, `+
+		colMaxTTLSec+`, `+colDefOneTime+`, `+colRequiredAttr+`, `+colAttrsSchema+`, `+colIssuers+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		p.ID, p.OrgID, p.Name, p.Level, nonNil(p.AllowedZones), ttlSeconds(p.MaxTTL), p.DefaultOneTime, nonNil(p.RequiredAttrs), schemaOrNull(p.AttrsSchema), nonNil(p.Issuers))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return service.ErrPolicyExists
+	}
+	return err
+}
+
+// GetPolicy — политика организации по id или ErrPolicyNotFound
+func (s *Store) GetPolicy(ctx context.Context, orgID, id string) (service.Policy, error) {
+	p, err := scanPolicy(s.pool.QueryRow(ctx, `SELECT `+policyColumns+` FROM `+tablePolicies+` WHERE `+colOrgID+`=$1 AND `+colID+`=$2`, orgID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.Policy{}, service.ErrPolicyNotFound
+		}
+		return service.Policy{}, err
+	}
+	return p, nil
+}
+
+// ListPolicies — политики организации; пустой orgID не фильтрует
+func (s *Store) ListPolicies(ctx context.Context, orgID string) ([]service.Policy, error) {
+	w := &whereBuilder{}
+	if orgID != "" {
+		w.add(colOrgID+`=?`, orgID)
+	}
+	rows, err := s.pool.Query(ctx, `SELECT `+policyColumns+` FROM `+tablePolicies+w.sql()+` ORDER BY `+colID, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(r pgx.CollectableRow) (service.Policy, error) { return scanPolicy(r) })
+}
+
+// UpdatePolicy — замена правил политики; организация не меняется
+func (s *Store) UpdatePolicy(ctx context.Context, p service.Policy) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE `+tablePolicies+` SET `+colName+`=$3, `+colLevel+`=$4, `+colAllowedZones+`=$5, `+
+		colMaxTTLSec+`=$6, `+colDefOneTime+`=$7, `+colRequiredAttr+`=$8, `+colAttrsSchema+`=$9, `+colIssuers+`=$10, `+colUpdatedAt+`=now() `+
+		`WHERE `+colOrgID+`=$1 AND `+colID+`=$2`,
+		p.OrgID, p.ID, p.Name, p.Level, nonNil(p.AllowedZones), ttlSeconds(p.MaxTTL), p.DefaultOneTime, nonNil(p.RequiredAttrs), schemaOrNull(p.AttrsSchema), nonNil(p.Issuers))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return service.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// DeletePolicy — удаляет политику организации, если по ней нет Active/Suspended пропусков
+func (s *Store) DeletePolicy(ctx context.Context, orgID, id string) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM `+tablePolicies+` WHERE `+colOrgID+`=$1 AND `+colID+`=$2 FOR UPDATE`, orgID, id); err != nil {
+		return err
+	}
+	var inUse bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM `+tablePasses+` WHERE `+colOrgID+`=$1 AND `+colPolicyID+`=$2 AND `+colStatus+` = ANY($3))`,
+		orgID, id, revocableStatuses).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return service.ErrPolicyInUse
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM `+tablePolicies+` WHERE `+colOrgID+`=$1 AND `+colID+`=$2`, orgID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return service.ErrPolicyNotFound
+	}
+	return tx.Commit(ctx)
+}
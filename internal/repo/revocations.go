@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+	"github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// revocationsLockKey — advisory-lock, сериализующий запись журнала отзывов:
+// seq выдаются в порядке коммита, и дельта since=N не теряет событий
+const revocationsLockKey int64 = 0x7265766f6b65
+
+func lockRevocations(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, revocationsLockKey)
+	return err
+}
+
+// insertRevocationEvents — события отзыва для уже переведённых в Revoked пропусков
+func insertRevocationEvents(ctx context.Context, tx pgx.Tx, ids []string) error {
+	_, err := tx.Exec(ctx, `INSERT INTO `+tableRevocations+` (`+colPassID+`, `+colOrgID+`, `+colExp+`, `+colRevokedAt+`, `+colReason+`)
+SELECT `+colID+`, `+colOrgID+`, `+colExp+`, COALESCE(`+colRevokedAt+`, now()), `+colRevReason+` FROM `+tablePasses+` WHERE `+colID+` = ANY($1::uuid[]) ORDER BY `+colID, ids)
+	return err
+}
+
+// ListRevocations — события отзыва с seq > since по ещё не истёкшим пропускам;
+// version — последний seq журнала на момент чтения
+func (s *Store) ListRevocations(ctx context.Context, orgID string, since int64, now time.Time) ([]service.RevocationEntry, int64, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+
+	var version int64
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(max(`+colSeq+`), 0) FROM `+tableRevocations).Scan(&version); err != nil {
+		return nil, 0, err
+	}
+	w := &whereBuilder{}
+	w.add(colSeq+` > ?`, since)
+	w.add(colExp+` > ?`, now)
+	if orgID != "" {
+		w.add(colOrgID+`=?`, orgID)
+	}
+	rows, err := tx.Query(ctx, `SELECT `+colSeq+`, `+colPassID+`::text, `+colExp+`, `+colRevokedAt+`, COALESCE(`+colReason+`, '') FROM `+tableRevocations+w.sql()+` ORDER BY `+colSeq, w.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []service.RevocationEntry
+	for rows.Next() {
+		var e service.RevocationEntry
+		if err := rows.Scan(&e.Seq, &e.PassID, &e.EXP, &e.RevokedAt, &e.Reason); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return out, version, nil
+}
+
+// BulkRevoke — блокирует и отзывает все Active/Suspended пропуска по фильтру одной транзакцией;
+// при dryRun только возвращает найденные id
+func (s *Store) BulkRevoke(ctx context.Context, f service.BulkRevokeFilter, meta service.RevocationMeta, dryRun bool) ([]string, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if !dryRun {
+		if err := lockRevocations(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &whereBuilder{}
+	w.add(colStatus+` = ANY(?)`, revocableStatuses)
+	if f.OrgID != "" {
+		w.add(colOrgID+`=?`, f.OrgID)
+	}
+	if f.SubjectName != "" {
+		w.add(colSubjectName+`=?`, f.SubjectName)
+	}
+	if f.ZoneID != "" {
+		w.add(zoneMatch, f.ZoneID)
+	}
+	if f.PolicyID != "" {
+		w.add(colPolicyID+`=?`, f.PolicyID)
+	}
+	if f.IssuerKeyID != "" {
+		w.add(colIssuerKeyID+`=?`, f.IssuerKeyID)
+	}
+	q := `SELECT ` + colID + `::text FROM ` + tablePasses + w.sql() + ` ORDER BY ` + colID
+	if !dryRun {
+		q += ` FOR UPDATE`
+	}
+	rows, err := tx.Query(ctx, q, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(ids) == 0 {
+		return ids, nil
+	}
+
+	cmd := `UPDATE ` + tablePasses + ` SET ` + revokeSet + ` WHERE ` + colID + ` = ANY($5::uuid[])`
+	if _, err := tx.Exec(ctx, cmd, string(im.StatusRevoked), string(meta.Reason), nullIfEmpty(meta.Note), nullIfEmpty(meta.Actor), ids); err != nil {
+		return nil, err
+	}
+	if err := insertRevocationEvents(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
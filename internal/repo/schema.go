@@ -4,6 +4,13 @@ const (
 	tableIssuerKeys   = "issuer_keys"
 	tablePasses       = "passes"
 	tablePickupTokens = "pickup_tokens"
+	tableRedemptions  = "pass_redemptions"
+	tableRevocations  = "revocation_events"
+	tableVersions     = "pass_versions"
+	tablePassZones    = "pass_zones"
+	tableZones        = "zones"
+	tablePolicies     = "policies"
+	tableIdemKeys     = "idempotency_keys"
 )
 
 const (
@@ -28,4 +35,41 @@ const (
 	colPassID       = "pass_id"
 	colTTLExpiresAt = "ttl_expires_at"
 	colUsedAt       = "used_at"
+	colRevokedAt    = "revoked_at"
+	colReaderID     = "reader_id"
+	colRedeemedAt   = "redeemed_at"
+	colMaxUses      = "max_uses"
+	colUses         = "uses"
+	colSeq          = "seq"
+	colStatusIndex  = "status_index"
+	colRevReason    = "revocation_reason"
+	colRevNote      = "revocation_note"
+	colRevokedBy    = "revoked_by"
+	colReason       = "reason"
+	colSuspendedAt  = "suspended_at"
+	colSuspendedBy  = "suspended_by"
+	colCompromised  = "compromised_at"
+	colReplacesID   = "replaces_id"
+	colVersion      = "version"
+	colSupersededAt = "superseded_at"
+	colSupersededBy = "superseded_by"
+	colZoneIDs      = "zone_ids"
+	colName         = "name"
+	colKind         = "kind"
+	colParentID     = "parent_id"
+	colLevel        = "level"
+	colAllowedZones = "allowed_zones"
+	colMaxTTLSec    = "max_ttl_s"
+	colDefOneTime   = "default_one_time"
+	colRequiredAttr = "required_attrs"
+	colIssuers      = "issuers"
+	colAttrsSchema  = "attrs_schema"
+	colSchedule     = "schedule"
+	colKey          = "key"
+	colActor        = "actor"
+	colRequestHash  = "request_hash"
+	colLeaseOwner   = "lease_owner"
+	colResponse     = "response"
+	colExpiresAt    = "expires_at"
+	colUpdatedAt    = "updated_at"
 )
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+// ListBlockedStatusIndexes — индексы отозванных и приостановленных пропусков и заменённых версий,
+// а также максимальный выданный индекс (-1, если нет). Список строится по текущему статусу,
+// поэтому после reinstate бит пропуска снова нулевой.
+func (s *Store) ListBlockedStatusIndexes(ctx context.Context) ([]int64, int64, error) {
+	var maxIndex int64
+	if err := s.pool.QueryRow(ctx, `SELECT COALESCE(GREATEST((SELECT max(`+colStatusIndex+`) FROM `+tablePasses+`), (SELECT max(`+colStatusIndex+`) FROM `+tableVersions+`)), -1)`).Scan(&maxIndex); err != nil {
+		return nil, 0, err
+	}
+	rows, err := s.pool.Query(ctx, `SELECT `+colStatusIndex+` FROM `+tablePasses+` WHERE `+colStatus+` IN ($1, $2) AND `+colStatusIndex+` IS NOT NULL
+UNION ALL SELECT `+colStatusIndex+` FROM `+tableVersions+` WHERE `+colStatusIndex+` IS NOT NULL`,
+		string(im.StatusRevoked), string(im.StatusSuspended))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var idx int64
+		if err := rows.Scan(&idx); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, idx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return out, maxIndex, nil
+}
@@ -3,6 +3,8 @@ package repo
 import (
 	"context"
 	"errors"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -22,6 +24,9 @@ func NewStore(pool *pgxpool.Pool) *Store { return &Store{pool: pool} }
 func (s *Store) GetActiveIssuerKey(ctx context.Context) (kid string, alg string, publicKey []byte, privateKey []byte, err error) {
 	err = s.pool.QueryRow(ctx, `SELECT `+colKeyID+`, `+colAlg+`, `+colPublicKey+`, `+colPrivateKey+` FROM `+tableIssuerKeys+` WHERE `+colStatus+`='active' ORDER BY `+colCreatedAt+` DESC LIMIT 1`).
 		Scan(&kid, &alg, &publicKey, &privateKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = service.ErrNoActiveKey
+	}
 	return
 }
 
@@ -43,32 +48,239 @@ func (s *Store) ListIssuerKeys(ctx context.Context) ([]service.IssuerKey, error)
 	return out, nil
 }
 
+// GetIssuerKey — активный или retired ключ по kid, иначе ErrNotFound
+func (s *Store) GetIssuerKey(ctx context.Context, kid string) (service.IssuerKey, error) {
+	var k service.IssuerKey
+	err := s.pool.QueryRow(ctx, `SELECT `+colKeyID+`, `+colAlg+`, `+colPublicKey+` FROM `+tableIssuerKeys+` WHERE `+colKeyID+`=$1 AND `+colStatus+` IN ('active','retired')`, kid).
+		Scan(&k.KID, &k.Alg, &k.PublicKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.IssuerKey{}, service.ErrNotFound
+		}
+		return service.IssuerKey{}, err
+	}
+	return k, nil
+}
+
+// NextStatusIndex — следующий свободный индекс в списке статусов
+func (s *Store) NextStatusIndex(ctx context.Context) (int64, error) {
+	var idx int64
+	err := s.pool.QueryRow(ctx, `SELECT nextval('pass_status_index_seq')`).Scan(&idx)
+	return idx, err
+}
+
 // PassWriter
 func (s *Store) InsertPass(ctx context.Context, p service.PassRecord) error {
-	cmd := `INSERT INTO ` + tablePasses + ` (` +
-		colID + `, ` + colOrgID + `, ` + colPolicyID + `, ` + colSubjectName + `, ` + colZoneID + `, ` +
-		colNbf + `, ` + colExp + `, ` + colOneTime + `, ` + colIssuerKeyID + `, ` + colSignature + `, ` + colPayload + `, ` + colStatus + `)
-            VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`
-	_, err := s.pool.Exec(ctx, cmd,
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if err := insertPass(ctx, tx, p); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// insertPass — строка пропуска и его зоны; вызывается внутри транзакции
+func insertPass(ctx context.Context, tx pgx.Tx, p service.PassRecord) error {
+	if _, err := tx.Exec(ctx, insertPassSQL, insertPassArgs(p)...); err != nil {
+		return err
+	}
+	return insertPassZones(ctx, tx, p.ID, p.ZoneIDs)
+}
+
+var insertPassSQL = `INSERT INTO ` + tablePasses + ` (` +
+	colID + `, ` + colOrgID + `, ` + colPolicyID + `, ` + colSubjectName + `, ` + colZoneID + `, ` +
+	colNbf + `, ` + colExp + `, ` + colOneTime + `, ` + colMaxUses + `, ` + colIssuerKeyID + `, ` + colStatusIndex + `, ` + colSignature + `, ` + colPayload + `, ` + colStatus + `, ` + colReplacesID + `, ` + colVersion + `, ` + colSchedule + `)
+            VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17)`
+
+var insertPassZonesSQL = `INSERT INTO ` + tablePassZones + ` (` + colPassID + `, ` + colZoneID + `)
+SELECT $1, z FROM unnest($2::text[]) AS z ON CONFLICT DO NOTHING`
+
+func insertPassArgs(p service.PassRecord) []any {
+	return []any{
 		p.ID, p.OrgID, p.PolicyID, p.SubjectName, p.ZoneID,
-		p.NBF, p.EXP, p.OneTime, p.IssuerKeyID, p.Signature, p.Payload,
-		string(im.StatusActive),
-	)
+		p.NBF, p.EXP, p.OneTime, nullIfZero(p.MaxUses), p.IssuerKeyID, p.StatusIndex, p.Signature, p.Payload,
+		string(im.StatusActive), nullIfEmpty(p.ReplacesID), max(p.Version, 1), p.Schedule,
+	}
+}
+
+// insertPassZones — зоны пропуска в pass_zones
+func insertPassZones(ctx context.Context, tx pgx.Tx, passID string, zoneIDs []string) error {
+	_, err := tx.Exec(ctx, insertPassZonesSQL, passID, zoneIDs)
 	return err
 }
 
-// RevokeActivePass — устанавливает статус Revoked, возвращает ErrNotFound/ErrConflict
-func (s *Store) RevokeActivePass(ctx context.Context, id string) error {
-	cmd := `UPDATE ` + tablePasses + ` SET ` + colStatus + `=$1 WHERE ` + colID + `=$2 AND ` + colStatus + `=$3`
-	tag, err := s.pool.Exec(ctx, cmd, string(im.StatusRevoked), id, string(im.StatusActive))
+// NextStatusIndexes — n следующих индексов в списке статусов одним запросом
+func (s *Store) NextStatusIndexes(ctx context.Context, n int) ([]int64, error) {
+	rows, err := s.pool.Query(ctx, `SELECT nextval('pass_status_index_seq') FROM generate_series(1, $1)`, n)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int64])
+}
+
+// InsertPasses — пропуска и их зоны одной транзакцией и одним пакетом запросов: либо все, либо ни одного
+func (s *Store) InsertPasses(ctx context.Context, ps []service.PassRecord) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return err
 	}
-	if tag.RowsAffected() == 1 {
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	b := &pgx.Batch{}
+	for _, p := range ps {
+		b.Queue(insertPassSQL, insertPassArgs(p)...)
+		b.Queue(insertPassZonesSQL, p.ID, p.ZoneIDs)
+	}
+	if err := tx.SendBatch(ctx, b).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// revokeSet — SET-часть отзыва; параметры $1..$4: статус, причина, комментарий, инициатор
+const revokeSet = colStatus + `=$1, ` + colRevokedAt + `=now(), ` + colRevReason + `=$2, ` + colRevNote + `=$3, ` + colRevokedBy + `=$4`
+
+// nullIfEmpty — пустая строка пишется как NULL
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullIfZero — 0 означает «не задано» и пишется как NULL
+func nullIfZero(n int) *int {
+	if n == 0 {
 		return nil
 	}
+	return &n
+}
+
+// RevokeActivePass — переводит Active или Suspended пропуск в Revoked с причиной и инициатором
+// и пишет событие в журнал отзывов, возвращает ErrNotFound/ErrConflict
+func (s *Store) RevokeActivePass(ctx context.Context, id string, meta service.RevocationMeta) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if err := lockRevocations(ctx, tx); err != nil {
+		return err
+	}
+	cmd := `UPDATE ` + tablePasses + ` SET ` + revokeSet + ` WHERE ` + colID + `=$5 AND ` + colStatus + ` = ANY($6)`
+	tag, err := tx.Exec(ctx, cmd, string(im.StatusRevoked), string(meta.Reason), nullIfEmpty(meta.Note), nullIfEmpty(meta.Actor),
+		id, revocableStatuses)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 1 {
+		if err := insertRevocationEvents(ctx, tx, []string{id}); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+	return notFoundOrConflict(ctx, tx, id)
+}
+
+// AmendPass — под блокировкой строки строит новую версию Active/Suspended пропуска через build,
+// переносит текущую версию в pass_versions и сохраняет новую под тем же id
+func (s *Store) AmendPass(ctx context.Context, id, actor string, build service.ReplaceFunc) (service.PassRecord, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return service.PassRecord{}, err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	v, err := scanPassView(tx.QueryRow(ctx, `SELECT `+passViewColumns+` FROM `+tablePasses+` WHERE `+colID+`=$1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.PassRecord{}, service.ErrNotFound
+		}
+		return service.PassRecord{}, err
+	}
+	if !slices.Contains(revocableStatuses, v.Status) {
+		return service.PassRecord{}, service.ErrConflict
+	}
+	rec, err := build(v)
+	if err != nil {
+		return service.PassRecord{}, err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO `+tableVersions+` (`+colPassID+`, `+colVersion+`, `+colZoneID+`, `+colZoneIDs+`, `+colSubjectName+`, `+colIssuerKeyID+`, `+colStatusIndex+`, `+colSignature+`, `+colPayload+`, `+colSupersededBy+`)
+SELECT `+colID+`, `+colVersion+`, `+colZoneID+`, $3, `+colSubjectName+`, `+colIssuerKeyID+`, `+colStatusIndex+`, `+colSignature+`, `+colPayload+`, $2 FROM `+tablePasses+` WHERE `+colID+`=$1`,
+		id, nullIfEmpty(actor), v.ZoneIDs); err != nil {
+		return service.PassRecord{}, err
+	}
+	cmd := `UPDATE ` + tablePasses + ` SET ` + colVersion + `=$2, ` + colZoneID + `=$3, ` + colSubjectName + `=$4, ` + colIssuerKeyID + `=$5, ` +
+		colStatusIndex + `=$6, ` + colSignature + `=$7, ` + colPayload + `=$8 WHERE ` + colID + `=$1`
+	if _, err := tx.Exec(ctx, cmd, id, rec.Version, rec.ZoneID, rec.SubjectName, rec.IssuerKeyID, rec.StatusIndex, rec.Signature, rec.Payload); err != nil {
+		return service.PassRecord{}, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM `+tablePassZones+` WHERE `+colPassID+`=$1`, id); err != nil {
+		return service.PassRecord{}, err
+	}
+	if err := insertPassZones(ctx, tx, id, rec.ZoneIDs); err != nil {
+		return service.PassRecord{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return service.PassRecord{}, err
+	}
+	return *rec, nil
+}
+
+// SupersedePass — под блокировкой строки строит замену Active пропуска через build, сохраняет её
+// и отзывает старый пропуск одной транзакцией
+func (s *Store) SupersedePass(ctx context.Context, id string, meta service.RevocationMeta, build service.ReplaceFunc) (service.PassRecord, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return service.PassRecord{}, err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if err := lockRevocations(ctx, tx); err != nil {
+		return service.PassRecord{}, err
+	}
+	v, err := scanPassView(tx.QueryRow(ctx, `SELECT `+passViewColumns+` FROM `+tablePasses+` WHERE `+colID+`=$1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.PassRecord{}, service.ErrNotFound
+		}
+		return service.PassRecord{}, err
+	}
+	if v.Status != string(im.StatusActive) {
+		return service.PassRecord{}, service.ErrConflict
+	}
+	rec, err := build(v)
+	if err != nil {
+		return service.PassRecord{}, err
+	}
+	if err := insertPass(ctx, tx, *rec); err != nil {
+		return service.PassRecord{}, err
+	}
+	cmd := `UPDATE ` + tablePasses + ` SET ` + revokeSet + ` WHERE ` + colID + `=$5`
+	if _, err := tx.Exec(ctx, cmd, string(im.StatusRevoked), string(meta.Reason), nullIfEmpty(meta.Note), nullIfEmpty(meta.Actor), id); err != nil {
+		return service.PassRecord{}, err
+	}
+	if err := insertRevocationEvents(ctx, tx, []string{id}); err != nil {
+		return service.PassRecord{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return service.PassRecord{}, err
+	}
+	return *rec, nil
+}
+
+// revocableStatuses — статусы, из которых допустим отзыв
+var revocableStatuses = []string{string(im.StatusActive), string(im.StatusSuspended)}
+
+// querier — общее у pgxpool.Pool и pgx.Tx для чтения
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// notFoundOrConflict — причина, по которой условный UPDATE не затронул пропуск
+func notFoundOrConflict(ctx context.Context, q querier, id string) error {
 	var exists bool
-	if err := s.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tablePasses+" WHERE "+colID+"=$1)", id).Scan(&exists); err != nil {
+	if err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tablePasses+" WHERE "+colID+"=$1)", id).Scan(&exists); err != nil {
 		return err
 	}
 	if !exists {
@@ -77,16 +289,136 @@ func (s *Store) RevokeActivePass(ctx context.Context, id string) error {
 	return service.ErrConflict
 }
 
-// GetPassStatus — возвращает статус или ErrNotFound
-func (s *Store) GetPassStatus(ctx context.Context, id string) (string, error) {
-	var status string
-	if err := s.pool.QueryRow(ctx, "SELECT "+colStatus+" FROM "+tablePasses+" WHERE "+colID+"=$1", id).Scan(&status); err != nil {
+// SuspendActivePass — Active → Suspended, возвращает ErrNotFound/ErrConflict
+func (s *Store) SuspendActivePass(ctx context.Context, id, actor string) error {
+	cmd := `UPDATE ` + tablePasses + ` SET ` + colStatus + `=$1, ` + colSuspendedAt + `=now(), ` + colSuspendedBy + `=$2 WHERE ` + colID + `=$3 AND ` + colStatus + `=$4`
+	tag, err := s.pool.Exec(ctx, cmd, string(im.StatusSuspended), nullIfEmpty(actor), id, string(im.StatusActive))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil
+	}
+	return notFoundOrConflict(ctx, s.pool, id)
+}
+
+// ReinstateSuspendedPass — Suspended → Active, пока не истёк exp; возвращает ErrNotFound/ErrConflict
+func (s *Store) ReinstateSuspendedPass(ctx context.Context, id string, now time.Time) error {
+	cmd := `UPDATE ` + tablePasses + ` SET ` + colStatus + `=$1, ` + colSuspendedAt + `=NULL, ` + colSuspendedBy + `=NULL WHERE ` + colID + `=$2 AND ` + colStatus + `=$3 AND ` + colExp + ` > $4`
+	tag, err := s.pool.Exec(ctx, cmd, string(im.StatusActive), id, string(im.StatusSuspended), now)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil
+	}
+	return notFoundOrConflict(ctx, s.pool, id)
+}
+
+// passZonesColumn — зоны пропуска массивом; основная зона первой
+const passZonesColumn = `ARRAY(SELECT z.` + colZoneID + ` FROM ` + tablePassZones + ` z WHERE z.` + colPassID + ` = ` + tablePasses + `.` + colID +
+	` ORDER BY z.` + colZoneID + ` <> ` + tablePasses + `.` + colZoneID + `, z.` + colZoneID + `)`
+
+// passViewColumns — колонки read-модели в порядке scanPassView
+const passViewColumns = colID + `::text, ` + colOrgID + `::text, ` + colPolicyID + `, ` + colSubjectName + `, ` + colZoneID + `, ` +
+	colNbf + `, ` + colExp + `, ` + colOneTime + `, COALESCE(` + colMaxUses + `, 0), ` + colSchedule + `, ` + colUses + `, ` + colVersion + `, ` + passZonesColumn + `, ` +
+	colIssuerKeyID + `, ` + colStatus + `, ` + colCreatedAt + `, ` + colRevokedAt + `, ` +
+	`COALESCE(` + colRevReason + `, ''), COALESCE(` + colRevNote + `, ''), COALESCE(` + colRevokedBy + `, ''), ` +
+	colSuspendedAt + `, COALESCE(` + colSuspendedBy + `, ''), COALESCE(` + colReplacesID + `::text, ''), ` + colPayload
+
+func scanPassView(row pgx.Row) (service.PassView, error) {
+	var v service.PassView
+	var payload []byte
+	if err := row.Scan(&v.ID, &v.OrgID, &v.PolicyID, &v.SubjectName, &v.ZoneID,
+		&v.NBF, &v.EXP, &v.OneTime, &v.MaxUses, &v.Schedule, &v.Uses, &v.Version, &v.ZoneIDs, &v.IssuerKeyID, &v.Status, &v.CreatedAt, &v.RevokedAt,
+		&v.RevocationReason, &v.RevocationNote, &v.RevokedBy, &v.SuspendedAt, &v.SuspendedBy, &v.ReplacesID, &payload); err != nil {
+		return service.PassView{}, err
+	}
+	v.Payload = string(payload)
+	return v, nil
+}
+
+// GetPass — read-модель пропуска или ErrNotFound
+func (s *Store) GetPass(ctx context.Context, id string) (service.PassView, error) {
+	v, err := scanPassView(s.pool.QueryRow(ctx, `SELECT `+passViewColumns+` FROM `+tablePasses+` WHERE `+colID+`=$1`, id))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", service.ErrNotFound
+			return service.PassView{}, service.ErrNotFound
+		}
+		return service.PassView{}, err
+	}
+	return v, nil
+}
+
+// ListPasses — страница по фильтру в порядке (exp, id), начиная после курсора
+func (s *Store) ListPasses(ctx context.Context, f service.PassFilter, after *service.PassCursor, limit int) ([]service.PassView, error) {
+	w := passFilterWhere(f)
+	if after != nil {
+		w.add(`(`+colExp+`, `+colID+`) > (?, ?)`, after.EXP, after.ID)
+	}
+	w.args = append(w.args, limit)
+	q := `SELECT ` + passViewColumns + ` FROM ` + tablePasses + w.sql() +
+		` ORDER BY ` + colExp + `, ` + colID + ` LIMIT $` + strconv.Itoa(len(w.args))
+	rows, err := s.pool.Query(ctx, q, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]service.PassView, 0, limit)
+	for rows.Next() {
+		v, err := scanPassView(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// exportFetchSize — сколько строк курсора выгрузки читается за один FETCH
+const exportFetchSize = 500
+
+// ExportPasses — пропуска по фильтру в порядке выпуска через серверный курсор: в памяти не больше
+// exportFetchSize строк. Выгрузка идёт в одной REPEATABLE READ транзакции — снимок не меняется
+// от изменений, сделанных во время чтения. Ошибка fn прерывает выгрузку.
+func (s *Store) ExportPasses(ctx context.Context, f service.PassFilter, fn func(service.PassView) error) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	w := passFilterWhere(f)
+	q := `DECLARE pass_export NO SCROLL CURSOR FOR SELECT ` + passViewColumns + ` FROM ` + tablePasses + w.sql() +
+		` ORDER BY ` + colCreatedAt + `, ` + colID
+	if _, err := tx.Exec(ctx, q, w.args...); err != nil {
+		return err
+	}
+	fetch := `FETCH ` + strconv.Itoa(exportFetchSize) + ` FROM pass_export`
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for rows.Next() {
+			n++
+			v, err := scanPassView(rows)
+			if err == nil {
+				err = fn(v)
+			}
+			if err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if n < exportFetchSize {
+			return tx.Commit(ctx)
 		}
-		return "", err
 	}
-	return status, nil
 }
 
 // InsertPickupToken — сохраняет pickup-token
@@ -95,7 +427,59 @@ func (s *Store) InsertPickupToken(ctx context.Context, token, passID string, exp
 	return err
 }
 
-// MarkTokenUsedAndGetPass — атомарно помечает токен и возвращает payload
+// RedeemPass — под блокировкой строки пропуска проверяет check, увеличивает uses и записывает проход.
+// Возвращает состояние пропуска после погашения.
+func (s *Store) RedeemPass(ctx context.Context, r service.RedemptionRecord, check service.RedeemCheck) (service.PassView, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return service.PassView{}, err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	v, err := scanPassView(tx.QueryRow(ctx, `SELECT `+passViewColumns+` FROM `+tablePasses+` WHERE `+colID+`=$1 FOR UPDATE`, r.PassID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.PassView{}, service.ErrNotFound
+		}
+		return service.PassView{}, err
+	}
+	if err := check(v); err != nil {
+		return service.PassView{}, err
+	}
+	if err := tx.QueryRow(ctx, `UPDATE `+tablePasses+` SET `+colUses+`=`+colUses+`+1 WHERE `+colID+`=$1 RETURNING `+colUses, r.PassID).Scan(&v.Uses); err != nil {
+		return service.PassView{}, err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO `+tableRedemptions+` (`+colPassID+`, `+colReaderID+`, `+colZoneID+`, `+colRedeemedAt+`) VALUES ($1,$2,$3,$4)`,
+		r.PassID, r.ReaderID, r.ZoneID, r.At); err != nil {
+		return service.PassView{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return service.PassView{}, err
+	}
+	return v, nil
+}
+
+// ExpireOverduePasses — переводит Active и Suspended пропуска с exp <= now в Expired
+func (s *Store) ExpireOverduePasses(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `UPDATE `+tablePasses+` SET `+colStatus+`=$1 WHERE `+colStatus+` = ANY($2) AND `+colExp+` <= $3`,
+		string(im.StatusExpired), revocableStatuses, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PurgePickupTokens — удаляет использованные и истёкшие pickup-токены
+func (s *Store) PurgePickupTokens(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM `+tablePickupTokens+` WHERE `+colUsedAt+` IS NOT NULL OR `+colTTLExpiresAt+` <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// MarkTokenUsedAndGetPass — атомарно помечает токен и возвращает payload;
+// если пропуск уже не Active или истёк (sweeper мог ещё не дойти), токен не расходуется
+// и возвращается ErrConflict, как в ApprovePass
 func (s *Store) MarkTokenUsedAndGetPass(ctx context.Context, token string) ([]byte, string, error) {
 	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -110,10 +494,14 @@ func (s *Store) MarkTokenUsedAndGetPass(ctx context.Context, token string) ([]by
 		return nil, "", err
 	}
 	var payload []byte
-	var kid string
-	if err := tx.QueryRow(ctx, `SELECT `+colPayload+`, `+colIssuerKeyID+` FROM `+tablePasses+` WHERE `+colID+`=$1`, passID).Scan(&payload, &kid); err != nil {
+	var kid, status string
+	var live bool
+	if err := tx.QueryRow(ctx, `SELECT `+colPayload+`, `+colIssuerKeyID+`, `+colStatus+`, `+colExp+` > now() FROM `+tablePasses+` WHERE `+colID+`=$1`, passID).Scan(&payload, &kid, &status, &live); err != nil {
 		return nil, "", err
 	}
+	if status != string(im.StatusActive) || !live {
+		return nil, "", service.ErrConflict
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return nil, "", err
 	}
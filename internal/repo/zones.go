@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	im "github.com/vbncursed/vkr/issue-service/internal/models"
+	"github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// SQLSTATE нарушений уникальности и внешнего ключа
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// zoneColumns — колонки зоны в порядке scanZone
+const zoneColumns = colID + `, ` + colOrgID + `::text, ` + colName + `, ` + colKind + `, COALESCE(` + colParentID + `, ''), ` + colCreatedAt
+
+func scanZone(row pgx.Row) (service.Zone, error) {
+	var z service.Zone
+	var kind string
+	if err := row.Scan(&z.ID, &z.OrgID, &z.Name, &kind, &z.ParentID, &z.CreatedAt); err != nil {
+		return service.Zone{}, err
+	}
+	z.Kind = im.ZoneKind(kind)
+	return z, nil
+}
+
+// InsertZone — новая зона; занятый в организации id — ErrZoneExists, исчезнувший родитель — ErrInvalidParent
+func (s *Store) InsertZone(ctx context.Context, z service.Zone) error {
+	_, err := s.pool.Exec(ctx, `INSERT INTO `+tableZones+` (`+colID+`, `+colOrgID+`, `+colName+`, `+colKind+`, `+colParentID+`) VALUES ($1,$2,$3,$4,$5)`,
+		z.ID, z.OrgID, z.Name, string(z.Kind), nullIfEmpty(z.ParentID))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return service.ErrZoneExists
+		case pgForeignKeyViolation:
+			return service.ErrInvalidParent
+		}
+	}
+	return err
+}
+
+// GetZone — зона организации по id или ErrZoneNotFound
+func (s *Store) GetZone(ctx context.Context, orgID, id string) (service.Zone, error) {
+	z, err := scanZone(s.pool.QueryRow(ctx, `SELECT `+zoneColumns+` FROM `+tableZones+` WHERE `+colOrgID+`=$1 AND `+colID+`=$2`, orgID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.Zone{}, service.ErrZoneNotFound
+		}
+		return service.Zone{}, err
+	}
+	return z, nil
+}
+
+// ListZones — зоны по организации и/или родителю; пустые условия не фильтруют
+func (s *Store) ListZones(ctx context.Context, orgID, parentID string) ([]service.Zone, error) {
+	w := &whereBuilder{}
+	if orgID != "" {
+		w.add(colOrgID+`=?`, orgID)
+	}
+	if parentID != "" {
+		w.add(colParentID+`=?`, parentID)
+	}
+	rows, err := s.pool.Query(ctx, `SELECT `+zoneColumns+` FROM `+tableZones+w.sql()+` ORDER BY `+colID, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(r pgx.CollectableRow) (service.Zone, error) { return scanZone(r) })
+}
+
+// UpdateZone — имя и родитель зоны
+func (s *Store) UpdateZone(ctx context.Context, z service.Zone) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE `+tableZones+` SET `+colName+`=$3, `+colParentID+`=$4 WHERE `+colOrgID+`=$1 AND `+colID+`=$2`,
+		z.OrgID, z.ID, z.Name, nullIfEmpty(z.ParentID))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+		return service.ErrInvalidParent
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return service.ErrZoneNotFound
+	}
+	return nil
+}
+
+// DeleteZone — удаляет зону, если у неё нет дочерних зон, на неё не выписаны Active/Suspended пропуска
+// и её не перечисляет allowed_zones ни одной политики (иначе ZoneReferencedError)
+func (s *Store) DeleteZone(ctx context.Context, orgID, id string) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM `+tableZones+` WHERE `+colOrgID+`=$1 AND `+colID+`=$2 FOR UPDATE`, orgID, id); err != nil {
+		return err
+	}
+	var inUse bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM `+tableZones+` WHERE `+colOrgID+`=$1 AND `+colParentID+`=$2)
+  OR EXISTS(SELECT 1 FROM `+tablePassZones+` z JOIN `+tablePasses+` p ON p.`+colID+` = z.`+colPassID+`
+    WHERE p.`+colOrgID+`=$1 AND z.`+colZoneID+`=$2 AND p.`+colStatus+` = ANY($3))`,
+		orgID, id, revocableStatuses).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return service.ErrZoneInUse
+	}
+	rows, err := tx.Query(ctx, `SELECT `+colID+` FROM `+tablePolicies+` WHERE `+colOrgID+`=$1 AND $2 = ANY(`+colAllowedZones+`) ORDER BY `+colID, orgID, id)
+	if err != nil {
+		return err
+	}
+	policies, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(policies) > 0 {
+		return &service.ZoneReferencedError{ZoneID: id, PolicyIDs: policies}
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM `+tableZones+` WHERE `+colOrgID+`=$1 AND `+colID+`=$2`, orgID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return service.ErrZoneNotFound
+	}
+	return tx.Commit(ctx)
+}
+
+// ResolveZones — те из ids, что принадлежат организации; с expand — вместе со всеми потомками
+func (s *Store) ResolveZones(ctx context.Context, orgID string, ids []string, expand bool) ([]string, error) {
+	q := `SELECT ` + colID + ` FROM ` + tableZones + ` WHERE ` + colID + ` = ANY($1) AND ` + colOrgID + `=$2`
+	if expand {
+		q = `WITH RECURSIVE t AS (` + q + `
+  UNION SELECT z.` + colID + ` FROM ` + tableZones + ` z JOIN t ON z.` + colParentID + ` = t.` + colID + ` WHERE z.` + colOrgID + `=$2
+) SELECT ` + colID + ` FROM t`
+	}
+	rows, err := s.pool.Query(ctx, q, ids, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
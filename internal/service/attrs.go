@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// attrsSchemaURL — условный адрес схемы attrs внутри компилятора
+const attrsSchemaURL = "urn:issue-service:policy-attrs"
+
+// schemaMessages — тексты ошибок валидации на английском, как и остальные сообщения API
+var schemaMessages = message.NewPrinter(language.English)
+
+// compileAttrsSchema — JSON Schema атрибутов политики (по умолчанию draft 2020-12).
+// Внешние $ref не загружаются: схема должна быть самодостаточной.
+func compileAttrsSchema(raw json.RawMessage) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, &InvalidAttrsSchemaError{Reason: err.Error()}
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return nil, &InvalidAttrsSchemaError{Reason: "schema must be a JSON object"}
+	}
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := c.AddResource(attrsSchemaURL, doc); err != nil {
+		return nil, &InvalidAttrsSchemaError{Reason: err.Error()}
+	}
+	sch, err := c.Compile(attrsSchemaURL)
+	if err != nil {
+		return nil, &InvalidAttrsSchemaError{Reason: err.Error()}
+	}
+	return sch, nil
+}
+
+// schemaCache — скомпилированные схемы attrs по организации и id политики. Запись годна, пока у политики
+// те же updated_at и текст схемы: PUT /policies/{id} меняет оба, и схема компилируется заново.
+type schemaCache struct {
+	mu sync.RWMutex
+	m  map[schemaKey]cachedSchema
+}
+
+type schemaKey struct{ orgID, policyID string }
+
+type cachedSchema struct {
+	updatedAt time.Time
+	raw       string
+	sch       *jsonschema.Schema
+}
+
+func newSchemaCache() *schemaCache {
+	return &schemaCache{m: make(map[schemaKey]cachedSchema)}
+}
+
+// get — схема attrs политики p, из кэша или только что скомпилированная
+func (c *schemaCache) get(p Policy) (*jsonschema.Schema, error) {
+	k := schemaKey{p.OrgID, p.ID}
+	c.mu.RLock()
+	e, ok := c.m[k]
+	c.mu.RUnlock()
+	if ok && e.updatedAt.Equal(p.UpdatedAt) && e.raw == string(p.AttrsSchema) {
+		return e.sch, nil
+	}
+	sch, err := compileAttrsSchema(p.AttrsSchema)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.m[k] = cachedSchema{updatedAt: p.UpdatedAt, raw: string(p.AttrsSchema), sch: sch}
+	c.mu.Unlock()
+	return sch, nil
+}
+
+// attrsViolations — обязательные attrs и нарушения JSON Schema политики, по одному на поле
+func (s *Service) attrsViolations(p Policy, attrs map[string]any) ([]Violation, error) {
+	var out []Violation
+	for _, name := range p.RequiredAttrs {
+		if _, ok := attrs[name]; !ok {
+			out = append(out, Violation{Field: "attrs." + name, Message: "required by policy"})
+		}
+	}
+	if len(p.AttrsSchema) == 0 {
+		return out, nil
+	}
+	sch, err := s.schemas.get(p)
+	if err != nil {
+		return nil, err
+	}
+	if attrs == nil {
+		attrs = map[string]any{}
+	}
+	// через JSON, чтобы числа и вложенные значения были в том виде, в каком их подпишем
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	var ve *jsonschema.ValidationError
+	if err := sch.Validate(inst); !errors.As(err, &ve) {
+		return out, err
+	}
+	return append(out, schemaViolations(ve)...), nil
+}
+
+// schemaViolations — листовые ошибки валидации; поле — путь в attrs через точку.
+// Ошибки required и additionalProperties указывают на объект, а не на само поле.
+func schemaViolations(ve *jsonschema.ValidationError) []Violation {
+	if len(ve.Causes) > 0 {
+		var out []Violation
+		for _, c := range ve.Causes {
+			out = append(out, schemaViolations(c)...)
+		}
+		return out
+	}
+	field := strings.Join(append([]string{"attrs"}, ve.InstanceLocation...), ".")
+	return []Violation{{Field: field, Message: ve.ErrorKind.LocalizedString(schemaMessages)}}
+}
@@ -0,0 +1,83 @@
+package service
+
+import "context"
+
+// IssueBatchItem — итог выпуска одного элемента пакета: Result при успехе, иначе Err
+type IssueBatchItem struct {
+	Result IssuePassResult
+	Err    error
+}
+
+// IssuePassBatch — выпуск пакета: каждый элемент проверяется как в IssuePass, подписываются все
+// одним активным ключом и записываются одной транзакцией. Ошибка элемента не мешает остальным;
+// с allOrNothing любая ошибка отменяет весь пакет, а прочие элементы получают ErrBatchAborted.
+// Ошибка ключа или записи возвращается целиком — тогда не выпущен ни один пропуск.
+func (s *Service) IssuePassBatch(ctx context.Context, cmds []IssuePassCommand, allOrNothing bool) ([]IssueBatchItem, error) {
+	items := make([]IssueBatchItem, len(cmds))
+	// политика читается один раз на пакет
+	type policyKey struct{ orgID, id string }
+	cache := map[policyKey]Policy{}
+	getPolicy := func(ctx context.Context, orgID, id string) (Policy, error) {
+		k := policyKey{orgID, id}
+		if p, ok := cache[k]; ok {
+			return p, nil
+		}
+		p, err := s.policies.GetPolicy(ctx, orgID, id)
+		if err == nil {
+			cache[k] = p
+		}
+		return p, err
+	}
+
+	drafts := make([]issueDraft, 0, len(cmds))
+	pos := make([]int, 0, len(cmds))
+	failed := false
+	for i, cmd := range cmds {
+		d, err := s.prepareIssue(ctx, cmd, getPolicy)
+		if err != nil {
+			items[i].Err = err
+			failed = true
+			continue
+		}
+		drafts = append(drafts, d)
+		pos = append(pos, i)
+	}
+	if failed && allOrNothing {
+		AbortBatch(items)
+		return items, nil
+	}
+	if len(drafts) == 0 {
+		return items, nil
+	}
+
+	kid, priv, err := s.signingKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := s.passes.NextStatusIndexes(ctx, len(drafts))
+	if err != nil {
+		return nil, err
+	}
+	recs := make([]PassRecord, len(drafts))
+	for j, d := range drafts {
+		if recs[j], err = s.signIssue(d, kid, priv, indexes[j]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.passes.InsertPasses(ctx, recs); err != nil {
+		return nil, err
+	}
+	for j, rec := range recs {
+		items[pos[j]].Result = issueResult(rec)
+	}
+	return items, nil
+}
+
+// AbortBatch — элементы без собственной ошибки получают ErrBatchAborted
+func AbortBatch(items []IssueBatchItem) {
+	for i := range items {
+		if items[i].Err == nil {
+			items[i].Err = ErrBatchAborted
+		}
+	}
+}
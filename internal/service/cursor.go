@@ -0,0 +1,33 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// encodeCursor упаковывает позицию страницы в непрозрачный токен
+func encodeCursor(c PassCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor разбирает токен, выданный encodeCursor
+func decodeCursor(token string) (*PassCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c PassCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.EXP.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
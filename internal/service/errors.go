@@ -1,11 +1,99 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
-	ErrUnsupportedAlg = errors.New("unsupported_alg")
-	ErrNotFound       = errors.New("not_found")
-	ErrConflict       = errors.New("conflict")
-	ErrInvalidToken   = errors.New("invalid_token")
-	ErrExpiredOrUsed  = errors.New("expired_or_used")
+	ErrUnsupportedAlg  = errors.New("unsupported_alg")
+	ErrNotFound        = errors.New("not_found")
+	ErrKeyNotFound     = errors.New("key_not_found")
+	ErrNoActiveKey     = errors.New("no_active_key")
+	ErrConflict        = errors.New("conflict")
+	ErrInvalidToken    = errors.New("invalid_token")
+	ErrExpiredOrUsed   = errors.New("expired_or_used")
+	ErrInvalidCursor   = errors.New("invalid_cursor")
+	ErrInvalidReason   = errors.New("invalid_reason")
+	ErrEmptyFilter     = errors.New("empty_filter")
+	ErrNotExtended     = errors.New("not_extended")
+	ErrNothingToAmend  = errors.New("nothing_to_amend")
+	ErrNoZones         = errors.New("no_zones")
+	ErrTooManyZones    = errors.New("too many zones")
+	ErrNbfAfterExp     = errors.New("nbf must be before exp")
+	ErrInvalidMaxUses  = errors.New("invalid max_uses")
+	ErrUnknownZone     = errors.New("unknown_zone")
+	ErrZoneNotFound    = errors.New("zone_not_found")
+	ErrZoneExists      = errors.New("zone_exists")
+	ErrZoneInUse       = errors.New("zone_in_use")
+	ErrInvalidParent   = errors.New("invalid_parent")
+	ErrUnknownPolicy   = errors.New("unknown_policy")
+	ErrPolicyNotFound  = errors.New("policy_not_found")
+	ErrPolicyExists    = errors.New("policy_exists")
+	ErrPolicyInUse     = errors.New("policy_in_use")
+	ErrIssuerDenied    = errors.New("issuer_not_allowed")
+	ErrPolicyViolation = errors.New("policy_violation")
+	ErrInvalidSchema   = errors.New("invalid_attrs_schema")
+	ErrOutsideSchedule = errors.New("outside_schedule")
+	ErrIdemMismatch    = errors.New("idempotency_key_reused")
+	ErrIdemInProgress  = errors.New("idempotency_key_in_progress")
+	ErrBatchAborted    = errors.New("batch_aborted")
+	ErrAlreadyRedeemed = errors.New("already_redeemed")
+	ErrExhausted       = errors.New("exhausted")
+	ErrOutsideValidity = errors.New("outside_validity")
+	ErrZoneNotAllowed  = errors.New("zone_not_allowed")
 )
+
+// UnknownZonesError — зоны, которых нет в реестре организации; errors.Is(err, ErrUnknownZone)
+type UnknownZonesError struct {
+	IDs []string
+}
+
+func (e *UnknownZonesError) Error() string {
+	return "unknown zones: " + strings.Join(e.IDs, ", ")
+}
+
+func (e *UnknownZonesError) Unwrap() error { return ErrUnknownZone }
+
+// ZoneReferencedError — удаляемую зону перечисляют allowed_zones политик; errors.Is(err, ErrConflict)
+type ZoneReferencedError struct {
+	ZoneID    string
+	PolicyIDs []string
+}
+
+func (e *ZoneReferencedError) Error() string {
+	return "zone " + e.ZoneID + " is referenced by policies: " + strings.Join(e.PolicyIDs, ", ")
+}
+
+func (e *ZoneReferencedError) Unwrap() error { return ErrConflict }
+
+// Violation — нарушенное правило политики по полю запроса
+type Violation struct {
+	Field   string
+	Message string
+}
+
+// PolicyViolationError — все нарушения политики разом; errors.Is(err, ErrPolicyViolation)
+type PolicyViolationError struct {
+	PolicyID   string
+	Violations []Violation
+}
+
+func (e *PolicyViolationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Message)
+	}
+	return "policy " + e.PolicyID + " violated: " + strings.Join(parts, "; ")
+}
+
+func (e *PolicyViolationError) Unwrap() error { return ErrPolicyViolation }
+
+// InvalidAttrsSchemaError — attrs_schema политики не компилируется; errors.Is(err, ErrInvalidSchema)
+type InvalidAttrsSchemaError struct {
+	Reason string
+}
+
+func (e *InvalidAttrsSchemaError) Error() string { return "invalid attrs_schema: " + e.Reason }
+
+func (e *InvalidAttrsSchemaError) Unwrap() error { return ErrInvalidSchema }
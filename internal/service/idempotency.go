@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// idempotencyLease — сколько ключ может оставаться незавершённым; после этого считается, что
+// первый запрос оборвался, и ключ можно занять заново
+const idempotencyLease = 2 * time.Minute
+
+// IssuePassOnce — IssuePass с ключом идемпотентности: повтор с тем же телом в пределах
+// IdempotencyTTL возвращает сохранённый результат (replayed=true) без нового пропуска.
+// Тот же ключ с другим телом — ErrIdemMismatch, пока первый запрос выполняется — ErrIdemInProgress;
+// ErrIdemInProgress и тогда, когда аренду ключа перехватили до записи пропуска — он не выпускается.
+// Ключ действует в пределах org_id и инициатора команды.
+func (s *Service) IssuePassOnce(ctx context.Context, idemKey string, cmd IssuePassCommand) (res IssuePassResult, replayed bool, err error) {
+	key := IdempotencyKey{OrgID: cmd.OrgID, Actor: cmd.Actor, Key: idemKey}
+	hash, err := commandHash(cmd)
+	if err != nil {
+		return IssuePassResult{}, false, err
+	}
+	// аренду ключа могут перехватить, если запрос выполняется дольше idempotencyLease:
+	// завершить ключ можно только по своей метке
+	owner := uuid.NewString()
+	existing, err := s.idem.ReserveIdempotencyKey(ctx, key, owner, hash, s.clock.Now().UTC(), s.opts.IdempotencyTTL, idempotencyLease)
+	if err != nil {
+		return IssuePassResult{}, false, err
+	}
+	if existing != nil {
+		if !bytes.Equal(existing.RequestHash, hash) {
+			return IssuePassResult{}, false, ErrIdemMismatch
+		}
+		if existing.Response == nil {
+			return IssuePassResult{}, false, ErrIdemInProgress
+		}
+		if err := json.Unmarshal(existing.Response, &res); err != nil {
+			return IssuePassResult{}, false, err
+		}
+		return res, true, nil
+	}
+
+	// пропуск и ответ для повтора сохраняются одной транзакцией: выпущенный пропуск без
+	// сохранённого ответа дал бы при повторе второй пропуск
+	res, err = s.issue(ctx, cmd, func(ctx context.Context, rec PassRecord) error {
+		b, err := json.Marshal(issueResult(rec))
+		if err != nil {
+			return err
+		}
+		return s.idem.InsertPassCompletingKey(ctx, key, owner, rec, b)
+	})
+	if err != nil {
+		// отказ не запоминаем: клиент исправит запрос или повторит его с тем же ключом
+		_ = s.idem.ReleaseIdempotencyKey(context.WithoutCancel(ctx), key, owner)
+		return IssuePassResult{}, false, err
+	}
+	return res, false, nil
+}
+
+// commandHash — отпечаток команды; json сортирует ключи attrs, так что порядок полей в теле не важен
+func commandHash(cmd IssuePassCommand) ([]byte, error) {
+	b, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// понедельник, 10:00 UTC
+var testNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+const testOrg = "00000000-0000-0000-0000-000000000001"
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+type fakeKeys struct {
+	KeyRepository
+	priv ed25519.PrivateKey
+}
+
+func (k *fakeKeys) GetActiveIssuerKey(context.Context) (string, string, []byte, []byte, error) {
+	return "k1", "EdDSA", k.priv.Public().(ed25519.PublicKey), k.priv, nil
+}
+
+type fakePasses struct {
+	PassRepository
+	next     int64
+	inserted []PassRecord
+}
+
+func (p *fakePasses) NextStatusIndex(context.Context) (int64, error) {
+	p.next++
+	return p.next, nil
+}
+
+func (p *fakePasses) InsertPass(_ context.Context, rec PassRecord) error {
+	p.inserted = append(p.inserted, rec)
+	return nil
+}
+
+type fakeZones struct {
+	ZoneRepository
+	known []string
+}
+
+func (z *fakeZones) ResolveZones(_ context.Context, _ string, ids []string, _ bool) ([]string, error) {
+	var out []string
+	for _, id := range ids {
+		if slices.Contains(z.known, id) {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+type fakePolicies struct{ PolicyRepository }
+
+func (fakePolicies) GetPolicy(context.Context, string, string) (Policy, error) {
+	return Policy{}, ErrPolicyNotFound
+}
+
+type idemEntry struct {
+	hash      []byte
+	response  []byte
+	createdAt time.Time
+	expiresAt time.Time
+	owner     string
+}
+
+// fakeIdem — ключи идемпотентности в памяти с теми же правилами аренды, что в repo
+type fakeIdem struct {
+	keys   map[IdempotencyKey]*idemEntry
+	passes *fakePasses
+	// beforeComplete вызывается один раз перед завершением ключа — чтобы перехватить аренду
+	beforeComplete func()
+}
+
+func (f *fakeIdem) ReserveIdempotencyKey(_ context.Context, key IdempotencyKey, owner string, hash []byte, now time.Time, ttl, lease time.Duration) (*IdempotencyRecord, error) {
+	e, ok := f.keys[key]
+	if ok && now.Before(e.expiresAt) && (e.response != nil || e.createdAt.After(now.Add(-lease))) {
+		return &IdempotencyRecord{Key: key, RequestHash: e.hash, Response: e.response, CreatedAt: e.createdAt}, nil
+	}
+	f.keys[key] = &idemEntry{hash: hash, createdAt: now, expiresAt: now.Add(ttl), owner: owner}
+	return nil, nil
+}
+
+func (f *fakeIdem) InsertPassCompletingKey(ctx context.Context, key IdempotencyKey, owner string, p PassRecord, response []byte) error {
+	if hook := f.beforeComplete; hook != nil {
+		f.beforeComplete = nil
+		hook()
+	}
+	e, ok := f.keys[key]
+	if !ok || e.owner != owner || e.response != nil {
+		return ErrIdemInProgress
+	}
+	e.response = response
+	return f.passes.InsertPass(ctx, p)
+}
+
+func (f *fakeIdem) ReleaseIdempotencyKey(_ context.Context, key IdempotencyKey, owner string) error {
+	if e, ok := f.keys[key]; ok && e.owner == owner && e.response == nil {
+		delete(f.keys, key)
+	}
+	return nil
+}
+
+func (f *fakeIdem) PurgeIdempotencyKeys(context.Context, time.Time) (int64, error) { return 0, nil }
+
+func newIdemService(t *testing.T) (*Service, *fakeIdem, *fakeClock) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passes := &fakePasses{}
+	idem := &fakeIdem{keys: map[IdempotencyKey]*idemEntry{}, passes: passes}
+	clock := &fakeClock{now: testNow}
+	svc := New(&fakeKeys{priv: priv}, passes, &fakeZones{known: []string{"lobby", "floor-3"}}, fakePolicies{}, idem,
+		clock, JWSSigner{}, JWSVerifier{}, Options{MaxTTL: 24 * time.Hour, IdempotencyTTL: 24 * time.Hour})
+	return svc, idem, clock
+}
+
+func issueCmd() IssuePassCommand {
+	return IssuePassCommand{
+		OrgID:       testOrg,
+		PolicyID:    "visitors",
+		SubjectName: "Иван Иванов",
+		ZoneIDs:     []string{"lobby"},
+		NBF:         testNow,
+		EXP:         testNow.Add(8 * time.Hour),
+		Attrs:       map[string]any{"shift": "day", "car": "А123ВС"},
+		Actor:       "desk",
+	}
+}
+
+func TestCommandHash(t *testing.T) {
+	base, err := commandHash(issueCmd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	oneTime := true
+	tests := []struct {
+		name   string
+		mutate func(c *IssuePassCommand)
+		same   bool
+	}{
+		{name: "same command", same: true},
+		{name: "attrs in other order", mutate: func(c *IssuePassCommand) { c.Attrs = map[string]any{"car": "А123ВС", "shift": "day"} }, same: true},
+		{name: "other subject", mutate: func(c *IssuePassCommand) { c.SubjectName = "Пётр Петров" }},
+		{name: "other zone", mutate: func(c *IssuePassCommand) { c.ZoneIDs = []string{"floor-3"} }},
+		{name: "zones in other order", mutate: func(c *IssuePassCommand) { c.ZoneIDs = []string{"floor-3", "lobby"} }},
+		{name: "expand zones", mutate: func(c *IssuePassCommand) { c.ExpandZones = true }},
+		{name: "other exp", mutate: func(c *IssuePassCommand) { c.EXP = c.EXP.Add(time.Minute) }},
+		{name: "other policy", mutate: func(c *IssuePassCommand) { c.PolicyID = "staff" }},
+		{name: "one time", mutate: func(c *IssuePassCommand) { c.OneTime = &oneTime }},
+		{name: "other attr value", mutate: func(c *IssuePassCommand) { c.Attrs["shift"] = "night" }},
+		{name: "no attrs", mutate: func(c *IssuePassCommand) { c.Attrs = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := issueCmd()
+			if tt.mutate != nil {
+				tt.mutate(&cmd)
+			}
+			got, err := commandHash(cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(got, base) != tt.same {
+				t.Fatalf("hash equal = %v, want %v", !tt.same, tt.same)
+			}
+		})
+	}
+}
+
+func TestIssuePassOnce(t *testing.T) {
+	svc, idem, _ := newIdemService(t)
+	ctx := context.Background()
+
+	first, replayed, err := svc.IssuePassOnce(ctx, "k-1", issueCmd())
+	if err != nil || replayed {
+		t.Fatalf("first: replayed = %v, error = %v", replayed, err)
+	}
+	again, replayed, err := svc.IssuePassOnce(ctx, "k-1", issueCmd())
+	if err != nil || !replayed {
+		t.Fatalf("repeat: replayed = %v, error = %v", replayed, err)
+	}
+	if again != first {
+		t.Fatalf("repeat = %+v, want %+v", again, first)
+	}
+	if n := len(idem.passes.inserted); n != 1 {
+		t.Fatalf("passes inserted = %d, want 1", n)
+	}
+
+	other := issueCmd()
+	other.SubjectName = "Пётр Петров"
+	if _, _, err := svc.IssuePassOnce(ctx, "k-1", other); !errors.Is(err, ErrIdemMismatch) {
+		t.Fatalf("other body: error = %v, want %v", err, ErrIdemMismatch)
+	}
+
+	// тот же ключ другого инициатора — другой запрос
+	otherActor := issueCmd()
+	otherActor.Actor = "gate"
+	res, replayed, err := svc.IssuePassOnce(ctx, "k-1", otherActor)
+	if err != nil || replayed || res.ID == first.ID {
+		t.Fatalf("other actor: replayed = %v, error = %v", replayed, err)
+	}
+}
+
+func TestIssuePassOnceReleasesKeyOnError(t *testing.T) {
+	svc, idem, _ := newIdemService(t)
+	ctx := context.Background()
+
+	bad := issueCmd()
+	bad.ZoneIDs = []string{"server-room"}
+	if _, _, err := svc.IssuePassOnce(ctx, "k-1", bad); !errors.Is(err, ErrUnknownZone) {
+		t.Fatalf("unknown zone: error = %v, want %v", err, ErrUnknownZone)
+	}
+	if len(idem.keys) != 0 {
+		t.Fatal("failed request left the key reserved")
+	}
+	if _, replayed, err := svc.IssuePassOnce(ctx, "k-1", issueCmd()); err != nil || replayed {
+		t.Fatalf("retry: replayed = %v, error = %v", replayed, err)
+	}
+}
+
+// Запрос, который выполнялся дольше аренды, не может завершить ключ, перехваченный другим:
+// его пропуск не записывается, а ключ остаётся за перехватившим запросом
+func TestIssuePassOnceLostLease(t *testing.T) {
+	svc, idem, clock := newIdemService(t)
+	ctx := context.Background()
+
+	var second IssuePassResult
+	var secondErr error
+	idem.beforeComplete = func() {
+		clock.now = clock.now.Add(idempotencyLease + time.Second)
+		second, _, secondErr = svc.IssuePassOnce(ctx, "k-1", issueCmd())
+	}
+	if _, _, err := svc.IssuePassOnce(ctx, "k-1", issueCmd()); !errors.Is(err, ErrIdemInProgress) {
+		t.Fatalf("stale request: error = %v, want %v", err, ErrIdemInProgress)
+	}
+	if secondErr != nil {
+		t.Fatalf("second request: error = %v", secondErr)
+	}
+	if n := len(idem.passes.inserted); n != 1 || idem.passes.inserted[0].ID != second.ID {
+		t.Fatalf("passes inserted = %d, want only the second request's pass", n)
+	}
+
+	res, replayed, err := svc.IssuePassOnce(ctx, "k-1", issueCmd())
+	if err != nil || !replayed || res.ID != second.ID {
+		t.Fatalf("repeat: id = %s, replayed = %v, error = %v; want %s replayed", res.ID, replayed, err, second.ID)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"crypto/ed25519"
+	"crypto/rand"
 
 	"github.com/vbncursed/vkr/issue-service/internal/crypto"
 )
@@ -19,3 +20,23 @@ type JWSSigner struct{}
 func (JWSSigner) SignJWS(kid string, privateKey []byte, payload []byte) (string, []byte, error) {
 	return crypto.SignJWS(kid, ed25519.PrivateKey(privateKey), payload)
 }
+
+func (JWSSigner) GenerateKey() ([]byte, []byte, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	return pub, priv, err
+}
+
+// JWSVerifier — адаптер Verifier поверх internal/crypto
+type JWSVerifier struct{}
+
+func (JWSVerifier) ParseJWS(compact string) (string, string, []byte, error) {
+	hdr, payload, err := crypto.ParseJWS(compact)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return hdr.Kid, hdr.Alg, payload, nil
+}
+
+func (JWSVerifier) VerifyJWS(compact string, publicKey []byte) error {
+	return crypto.VerifyJWS(compact, ed25519.PublicKey(publicKey))
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	imodels "github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+type CompromiseKeyCommand struct {
+	KID     string
+	Reissue bool
+	Note    string
+	Actor   string
+}
+
+// CompromiseKeyReport — итог процедуры компрометации; NewKeyID пуст, если ключи не ротировались
+type CompromiseKeyReport struct {
+	KeyID    string
+	NewKeyID string
+	Revoked  int
+	Reissued int
+	Passes   []CompromisedPass
+}
+
+// CompromiseKey — аварийная процедура утечки ключа: ключ помечается compromised и пропадает из JWKS,
+// все его Active/Suspended пропуска отзываются с причиной compromised. Если ключ был активным или
+// запрошен перевыпуск, активным становится новый ключ; с Reissue для каждого бывшего Active пропуска,
+// который ещё действует, выпускается замена с тем же содержимым и остатком проходов.
+func (s *Service) CompromiseKey(ctx context.Context, cmd CompromiseKeyCommand) (CompromiseKeyReport, error) {
+	// без активного ключа процедура тоже нужна: activeKID остаётся пустым
+	activeKID, _, _, _, err := s.keys.GetActiveIssuerKey(ctx)
+	if err != nil && !errors.Is(err, ErrNoActiveKey) {
+		return CompromiseKeyReport{}, err
+	}
+
+	var fresh *NewIssuerKey
+	if cmd.Reissue || activeKID == cmd.KID {
+		fresh, err = s.newIssuerKey()
+		if err != nil {
+			return CompromiseKeyReport{}, err
+		}
+	}
+	var replace ReplaceFunc
+	if cmd.Reissue {
+		now := s.clock.Now().UTC()
+		replace = func(p PassView) (*PassRecord, error) {
+			if !p.EXP.After(now) || (p.MaxUses > 0 && p.Uses >= p.MaxUses) {
+				return nil, nil
+			}
+			return s.resign(ctx, p, fresh.KID, fresh.PrivateKey, func(body *imodels.SignedPayload) {
+				if p.MaxUses > 0 {
+					body.Pass.MaxUses = p.MaxUses - p.Uses
+				}
+			})
+		}
+	}
+
+	meta := RevocationMeta{Reason: imodels.RevocationCompromised, Note: cmd.Note, Actor: cmd.Actor}
+	passes, err := s.keys.CompromiseIssuerKey(ctx, cmd.KID, fresh, meta, replace)
+	if err != nil {
+		return CompromiseKeyReport{}, err
+	}
+	rep := CompromiseKeyReport{KeyID: cmd.KID, Revoked: len(passes), Passes: passes}
+	if fresh != nil {
+		rep.NewKeyID = fresh.KID
+	}
+	for _, p := range passes {
+		if p.Replacement != nil {
+			rep.Reissued++
+		}
+	}
+	return rep, nil
+}
+
+// newIssuerKey — свежий EdDSA ключ; суффикс kid исключает совпадение с ключами того же месяца
+func (s *Service) newIssuerKey() (*NewIssuerKey, error) {
+	pub, priv, err := s.signer.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	kid := "key-" + s.clock.Now().UTC().Format("2006-01") + "-" + hex.EncodeToString(suffix)
+	return &NewIssuerKey{KID: kid, Alg: "EdDSA", PublicKey: pub, PrivateKey: priv}, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// CreatePolicy — новая политика; allowed_zones должны быть в реестре зон организации,
+// attrs_schema — компилироваться
+func (s *Service) CreatePolicy(ctx context.Context, p Policy) (Policy, error) {
+	if err := s.checkPolicyRules(ctx, p); err != nil {
+		return Policy{}, err
+	}
+	if err := s.policies.InsertPolicy(ctx, p); err != nil {
+		return Policy{}, err
+	}
+	return s.policies.GetPolicy(ctx, p.OrgID, p.ID)
+}
+
+// GetPolicy — политика организации по id
+func (s *Service) GetPolicy(ctx context.Context, orgID, id string) (Policy, error) {
+	return s.policies.GetPolicy(ctx, orgID, id)
+}
+
+// ListPolicies — политики организации; пустой orgID — все
+func (s *Service) ListPolicies(ctx context.Context, orgID string) ([]Policy, error) {
+	return s.policies.ListPolicies(ctx, orgID)
+}
+
+// UpdatePolicy — полная замена правил политики организации p.OrgID.
+// Уже выпущенные пропуска не пересматриваются.
+func (s *Service) UpdatePolicy(ctx context.Context, p Policy) (Policy, error) {
+	if _, err := s.policies.GetPolicy(ctx, p.OrgID, p.ID); err != nil {
+		return Policy{}, err
+	}
+	if err := s.checkPolicyRules(ctx, p); err != nil {
+		return Policy{}, err
+	}
+	if err := s.policies.UpdatePolicy(ctx, p); err != nil {
+		return Policy{}, err
+	}
+	return s.policies.GetPolicy(ctx, p.OrgID, p.ID)
+}
+
+// DeletePolicy — удаление политики организации без Active/Suspended пропусков
+func (s *Service) DeletePolicy(ctx context.Context, orgID, id string) error {
+	return s.policies.DeletePolicy(ctx, orgID, id)
+}
+
+// checkPolicyRules — каждая разрешённая зона есть в реестре организации политики,
+// схема attrs корректна
+func (s *Service) checkPolicyRules(ctx context.Context, p Policy) error {
+	if len(p.AttrsSchema) > 0 {
+		if _, err := compileAttrsSchema(p.AttrsSchema); err != nil {
+			return err
+		}
+	}
+	if len(p.AllowedZones) == 0 {
+		return nil
+	}
+	_, err := s.resolveZones(ctx, p.OrgID, p.AllowedZones, false)
+	return err
+}
+
+// lookupPolicy — политика организации для выпуска, продления и PATCH. Незарегистрированная
+// ничего не ограничивает (действует только глобальный MaxTTL), а с StrictPolicies — ErrUnknownPolicy.
+func (s *Service) lookupPolicy(ctx context.Context, getPolicy policyGetter, orgID, id string) (Policy, error) {
+	p, err := getPolicy(ctx, orgID, id)
+	if errors.Is(err, ErrPolicyNotFound) {
+		if s.opts.StrictPolicies {
+			return Policy{}, ErrUnknownPolicy
+		}
+		return Policy{ID: id, OrgID: orgID}, nil
+	}
+	return p, err
+}
+
+// ttlLimit — предел exp-now для пропусков политики
+func (s *Service) ttlLimit(ctx context.Context, orgID, policyID string) (time.Duration, error) {
+	p, err := s.lookupPolicy(ctx, s.policies.GetPolicy, orgID, policyID)
+	if err != nil {
+		return 0, err
+	}
+	return p.maxTTL(s.opts.MaxTTL), nil
+}
+
+// maxTTL — собственный предел политики или глобальный
+func (p Policy) maxTTL(global time.Duration) time.Duration {
+	if p.MaxTTL > 0 {
+		return p.MaxTTL
+	}
+	return global
+}
+
+func ttlViolation(limit time.Duration) Violation {
+	return Violation{Field: "exp", Message: fmt.Sprintf("exceeds max ttl %s", limit)}
+}
+
+// checkPolicy — нарушения политики командой выпуска; зоны команды уже разрешены.
+// Разрешённые зоны покрывают и своих потомков.
+func (s *Service) checkPolicy(ctx context.Context, p Policy, cmd IssuePassCommand) ([]Violation, error) {
+	var out []Violation
+	if limit := p.maxTTL(s.opts.MaxTTL); cmd.EXP.Sub(s.clock.Now()) > limit {
+		out = append(out, ttlViolation(limit))
+	}
+	if len(p.AllowedZones) > 0 {
+		allowed, err := s.zones.ResolveZones(ctx, p.OrgID, p.AllowedZones, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, z := range cmd.ZoneIDs {
+			if !slices.Contains(allowed, z) {
+				out = append(out, Violation{Field: "zone_ids", Message: fmt.Sprintf("zone %s not allowed by policy", z)})
+			}
+		}
+	}
+	attrs, err := s.attrsViolations(p, cmd.Attrs)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, attrs...), nil
+}
+
+// checkAmendedAttrs — новые attrs пропуска соответствуют его политике
+func (s *Service) checkAmendedAttrs(ctx context.Context, orgID, policyID string, attrs map[string]any) error {
+	p, err := s.lookupPolicy(ctx, s.policies.GetPolicy, orgID, policyID)
+	if err != nil {
+		return err
+	}
+	violations, err := s.attrsViolations(p, attrs)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &PolicyViolationError{PolicyID: p.ID, Violations: violations}
+	}
+	return nil
+}
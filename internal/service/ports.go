@@ -2,59 +2,286 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"time"
+
+	imodels "github.com/vbncursed/vkr/issue-service/internal/models"
 )
 
+// Options — параметры сервиса, не зависящие от хранилища
+type Options struct {
+	// StatusListURL — публичный адрес списка статусов, встраивается в payload
+	StatusListURL string
+	// MaxTTL — предел exp-now, если политика не задаёт свой
+	MaxTTL time.Duration
+	// IdempotencyTTL — сколько ответ на запрос с Idempotency-Key доступен для повтора
+	IdempotencyTTL time.Duration
+	// StrictPolicies — policy_id обязан быть в реестре организации; иначе незарегистрированная
+	// политика ничего не ограничивает
+	StrictPolicies bool
+}
+
 // Clock — абстракция времени для тестируемости
 type Clock interface {
 	Now() time.Time
 }
 
-// Signer — абстракция подписи JWS
+// Signer — абстракция подписи JWS и генерации ключей под неё
 type Signer interface {
 	SignJWS(kid string, privateKey []byte, payload []byte) (compact string, signature []byte, err error)
+	GenerateKey() (publicKey []byte, privateKey []byte, err error)
+}
+
+// Verifier — разбор и проверка подписи JWS
+type Verifier interface {
+	ParseJWS(compact string) (kid string, alg string, payload []byte, err error)
+	VerifyJWS(compact string, publicKey []byte) error
 }
 
 // KeyRepository — доступ к ключам эмитента
 type KeyRepository interface {
 	GetActiveIssuerKey(ctx context.Context) (kid string, alg string, publicKey []byte, privateKey []byte, err error)
 	ListIssuerKeys(ctx context.Context) ([]IssuerKey, error)
+	GetIssuerKey(ctx context.Context, kid string) (IssuerKey, error)
+	CompromiseIssuerKey(ctx context.Context, kid string, fresh *NewIssuerKey, meta RevocationMeta, replace ReplaceFunc) ([]CompromisedPass, error)
 }
 
 // PassRepository — порт для всех операций над пропусками и токенами
 type PassRepository interface {
+	NextStatusIndex(ctx context.Context) (int64, error)
+	NextStatusIndexes(ctx context.Context, n int) ([]int64, error)
 	InsertPass(ctx context.Context, p PassRecord) error
-	RevokeActivePass(ctx context.Context, id string) error
-	GetPassStatus(ctx context.Context, id string) (string, error)
+	InsertPasses(ctx context.Context, ps []PassRecord) error
+	RevokeActivePass(ctx context.Context, id string, meta RevocationMeta) error
+	AmendPass(ctx context.Context, id, actor string, build ReplaceFunc) (PassRecord, error)
+	SupersedePass(ctx context.Context, id string, meta RevocationMeta, build ReplaceFunc) (PassRecord, error)
+	BulkRevoke(ctx context.Context, f BulkRevokeFilter, meta RevocationMeta, dryRun bool) (ids []string, err error)
+	SuspendActivePass(ctx context.Context, id, actor string) error
+	ReinstateSuspendedPass(ctx context.Context, id string, now time.Time) error
+	GetPass(ctx context.Context, id string) (PassView, error)
+	ListPasses(ctx context.Context, f PassFilter, after *PassCursor, limit int) ([]PassView, error)
+	ExportPasses(ctx context.Context, f PassFilter, fn func(PassView) error) error
 	InsertPickupToken(ctx context.Context, token, passID string, exp time.Time) error
 	MarkTokenUsedAndGetPass(ctx context.Context, token string) (payload []byte, kid string, err error)
+	RedeemPass(ctx context.Context, r RedemptionRecord, check RedeemCheck) (PassView, error)
+	ListRevocations(ctx context.Context, orgID string, since int64, now time.Time) (entries []RevocationEntry, version int64, err error)
+	ListBlockedStatusIndexes(ctx context.Context) (blocked []int64, maxIndex int64, err error)
+	ExpireOverduePasses(ctx context.Context, now time.Time) (int64, error)
+	PurgePickupTokens(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ZoneRepository — реестр зон
+type ZoneRepository interface {
+	InsertZone(ctx context.Context, z Zone) error
+	GetZone(ctx context.Context, orgID, id string) (Zone, error)
+	ListZones(ctx context.Context, orgID, parentID string) ([]Zone, error)
+	UpdateZone(ctx context.Context, z Zone) error
+	DeleteZone(ctx context.Context, orgID, id string) error
+	ResolveZones(ctx context.Context, orgID string, ids []string, expand bool) ([]string, error)
+}
+
+// PolicyRepository — реестр политик выпуска
+type PolicyRepository interface {
+	InsertPolicy(ctx context.Context, p Policy) error
+	GetPolicy(ctx context.Context, orgID, id string) (Policy, error)
+	ListPolicies(ctx context.Context, orgID string) ([]Policy, error)
+	UpdatePolicy(ctx context.Context, p Policy) error
+	DeletePolicy(ctx context.Context, orgID, id string) error
+}
+
+// IdempotencyRepository — ключи идемпотентности выпуска и сохранённые ответы. owner — метка
+// запроса, занявшего ключ: завершить или освободить ключ может только он.
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(ctx context.Context, key IdempotencyKey, owner string, requestHash []byte, now time.Time, ttl, lease time.Duration) (*IdempotencyRecord, error)
+	InsertPassCompletingKey(ctx context.Context, key IdempotencyKey, owner string, p PassRecord, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key IdempotencyKey, owner string) error
+	PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// IdempotencyKey — Idempotency-Key клиента в пространстве организации и инициатора:
+// одинаковые ключи разных организаций или X-Actor не пересекаются
+type IdempotencyKey struct {
+	OrgID string
+	Actor string
+	Key   string
 }
 
-// PassRecord — данные для сохранения пропуска (write-модель)
+// IdempotencyRecord — занятый ключ; Response=nil — первый запрос ещё выполняется
+type IdempotencyRecord struct {
+	Key         IdempotencyKey
+	RequestHash []byte
+	Response    []byte
+	CreatedAt   time.Time
+}
+
+// Policy — правила выпуска по policy_id. Пустые AllowedZones и Issuers не ограничивают,
+// MaxTTL=0 — действует глобальный MAX_TTL_H, пустая AttrsSchema не проверяет форму attrs.
+type Policy struct {
+	ID             string
+	OrgID          string
+	Name           string
+	Level          string
+	AllowedZones   []string
+	MaxTTL         time.Duration
+	DefaultOneTime bool
+	RequiredAttrs  []string
+	AttrsSchema    json.RawMessage
+	Issuers        []string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Zone — зона прохода; ParentID пуст у зданий
+type Zone struct {
+	ID        string
+	OrgID     string
+	Name      string
+	Kind      imodels.ZoneKind
+	ParentID  string
+	CreatedAt time.Time
+}
+
+// PassRecord — данные для сохранения пропуска (write-модель); MaxUses=0 — без лимита,
+// Version — номер подписанной версии под тем же id, ZoneID — первая из ZoneIDs
 type PassRecord struct {
 	ID          string
 	OrgID       string
 	PolicyID    string
 	SubjectName string
 	ZoneID      string
+	ZoneIDs     []string
 	NBF         time.Time
 	EXP         time.Time
 	OneTime     bool
+	MaxUses     int
+	Schedule    *imodels.Schedule
 	IssuerKeyID string
+	StatusIndex int64
+	Version     int
+	ReplacesID  string
 	Signature   []byte
 	Payload     []byte
 }
 
-// Команда и результат для кейса IssuePass
+// PassView — сохранённое состояние пропуска (read-модель); MaxUses=0 — без лимита
+type PassView struct {
+	ID               string
+	OrgID            string
+	PolicyID         string
+	SubjectName      string
+	ZoneID           string
+	ZoneIDs          []string
+	NBF              time.Time
+	EXP              time.Time
+	OneTime          bool
+	MaxUses          int
+	Schedule         *imodels.Schedule
+	Uses             int
+	Version          int
+	IssuerKeyID      string
+	Status           string
+	CreatedAt        time.Time
+	RevokedAt        *time.Time
+	RevocationReason string
+	RevocationNote   string
+	RevokedBy        string
+	SuspendedAt      *time.Time
+	SuspendedBy      string
+	ReplacesID       string
+	Payload          string
+}
+
+// PassFilter — условия выборки пропусков; пустые поля не фильтруют
+type PassFilter struct {
+	OrgID         string
+	PolicyID      string
+	ZoneID        string
+	Status        string
+	SubjectPrefix string
+	NBFFrom       *time.Time
+	NBFTo         *time.Time
+	EXPFrom       *time.Time
+	EXPTo         *time.Time
+	IssuedFrom    *time.Time
+	IssuedTo      *time.Time
+}
+
+// PassCursor — позиция keyset-пагинации по (exp, id)
+type PassCursor struct {
+	EXP time.Time `json:"exp"`
+	ID  string    `json:"id"`
+}
+
+// Запрос и страница для кейса ListPasses
+type ListPassesQuery struct {
+	Filter PassFilter
+	Cursor string
+	Limit  int
+}
+
+type ListPassesResult struct {
+	Items      []PassView
+	NextCursor string
+}
+
+// RedemptionRecord — факт прохода по пропуску
+type RedemptionRecord struct {
+	PassID   string
+	ReaderID string
+	ZoneID   string
+	At       time.Time
+}
+
+// RedeemCheck — бизнес-проверка погашения; вызывается хранилищем под блокировкой пропуска.
+// Ненулевая ошибка отменяет погашение.
+type RedeemCheck func(p PassView) error
+
+// RevocationMeta — кто, почему и с каким комментарием отзывает пропуск
+type RevocationMeta struct {
+	Reason imodels.RevocationReason
+	Note   string
+	Actor  string
+}
+
+// BulkRevokeFilter — условия массового отзыва; сравнение точное, пустые поля не фильтруют
+type BulkRevokeFilter struct {
+	OrgID       string
+	SubjectName string
+	ZoneID      string
+	PolicyID    string
+	IssuerKeyID string
+}
+
+// IsEmpty — ни одного условия: такой фильтр отозвал бы всё
+func (f BulkRevokeFilter) IsEmpty() bool {
+	return f == BulkRevokeFilter{}
+}
+
+// RevocationEntry — событие журнала отзывов
+type RevocationEntry struct {
+	Seq       int64
+	PassID    string
+	EXP       time.Time
+	RevokedAt time.Time
+	Reason    string
+}
+
+// Команда и результат для кейса IssuePass; ZoneIDs — зоны прохода, первая считается основной.
+// ExpandZones добавляет к ним все дочерние зоны, OneTime=nil — по умолчанию политики,
+// Schedule — повторяющиеся окна доступа внутри NBF..EXP.
 type IssuePassCommand struct {
 	OrgID       string
 	PolicyID    string
 	SubjectName string
-	ZoneID      string
+	ZoneIDs     []string
+	ExpandZones bool
 	NBF         time.Time
 	EXP         time.Time
-	OneTime     bool
+	OneTime     *bool
+	MaxUses     int
+	Schedule    *imodels.Schedule
 	Attrs       map[string]any
+	Actor       string
 }
 
 type IssuePassResult struct {
@@ -63,6 +290,24 @@ type IssuePassResult struct {
 	Payload     string
 }
 
+// NewIssuerKey — сгенерированный ключ, который станет активным
+type NewIssuerKey struct {
+	KID        string
+	Alg        string
+	PublicKey  []byte
+	PrivateKey []byte
+}
+
+// ReplaceFunc — строит замену отозванного пропуска; вызывается хранилищем внутри транзакции.
+// nil-запись — замена не нужна.
+type ReplaceFunc func(p PassView) (*PassRecord, error)
+
+// CompromisedPass — пропуск, отозванный из-за компрометации ключа; View — состояние до отзыва
+type CompromisedPass struct {
+	View        PassView
+	Replacement *PassRecord
+}
+
 // IssuerKey — доменная проекция ключа эмитента
 type IssuerKey struct {
 	KID       string
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	imodels "github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+// resign — новый пропуск с содержимым old под новым id, подписанный ключом kid; meta.replaces
+// указывает на old, версия сбрасывается в 1. edit может поменять payload до подписи.
+func (s *Service) resign(ctx context.Context, old PassView, kid string, priv []byte, edit func(body *imodels.SignedPayload)) (*PassRecord, error) {
+	passID := uuid.New().String()
+	return s.resignPayload(ctx, old, kid, priv, func(body *imodels.SignedPayload, rec *PassRecord) {
+		body.Pass.ID = passID
+		body.Pass.Version = 1
+		body.Meta.Replaces = old.ID
+		rec.ReplacesID = old.ID
+		if edit != nil {
+			edit(body)
+		}
+	})
+}
+
+// resignPayload — сохранённый payload old, переподписанный ключом kid с новыми nonce, issued_at
+// и индексом в списке статусов. edit меняет payload и запись до подписи; id, окно, лимит и версия
+// записи, как и расписание, берутся из payload после edit.
+func (s *Service) resignPayload(ctx context.Context, old PassView, kid string, priv []byte, edit func(body *imodels.SignedPayload, rec *PassRecord)) (*PassRecord, error) {
+	_, _, payloadB, err := s.verifier.ParseJWS(old.Payload)
+	if err != nil {
+		return nil, err
+	}
+	var body imodels.SignedPayload
+	if err := json.Unmarshal(payloadB, &body); err != nil {
+		return nil, err
+	}
+	statusIndex, err := s.passes.NextStatusIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	body.Pass.Status = &imodels.PayloadStatus{
+		Purpose: imodels.StatusPurposeRevocation,
+		List:    s.opts.StatusListURL,
+		Index:   statusIndex,
+	}
+	body.Meta.IssuedAt = s.clock.Now().UTC()
+	body.Meta.Nonce = nonce
+	body.IssuerKeyID = kid
+	rec := &PassRecord{
+		OrgID:       old.OrgID,
+		PolicyID:    old.PolicyID,
+		SubjectName: old.SubjectName,
+		ZoneID:      old.ZoneID,
+		ZoneIDs:     old.ZoneIDs,
+		OneTime:     old.OneTime,
+		IssuerKeyID: kid,
+		StatusIndex: statusIndex,
+	}
+	if edit != nil {
+		edit(&body, rec)
+	}
+	rec.ID = body.Pass.ID
+	rec.NBF = body.Pass.NBF.UTC()
+	rec.EXP = body.Pass.EXP.UTC()
+	rec.MaxUses = body.Pass.MaxUses
+	rec.Schedule = body.Pass.Schedule
+	rec.Version = max(body.Pass.Version, 1)
+
+	newB, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	compact, sig, err := s.signer.SignJWS(kid, priv, newB)
+	if err != nil {
+		return nil, err
+	}
+	rec.Signature = sig
+	rec.Payload = []byte(compact)
+	return rec, nil
+}
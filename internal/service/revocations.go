@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	imodels "github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+type RevocationListResult struct {
+	Version     int64
+	Since       int64
+	Count       int
+	IssuerKeyID string
+	Payload     string
+}
+
+// GetRevocationList — подписанный активным ключом список отозванных и ещё не истёкших пропусков.
+// orgID="" — по всем организациям; since>0 — только события после версии since.
+func (s *Service) GetRevocationList(ctx context.Context, orgID string, since int64) (RevocationListResult, error) {
+	kid, priv, err := s.signingKey(ctx)
+	if err != nil {
+		return RevocationListResult{}, err
+	}
+	now := s.clock.Now().UTC()
+	entries, version, err := s.passes.ListRevocations(ctx, orgID, since, now)
+	if err != nil {
+		return RevocationListResult{}, err
+	}
+	body := imodels.RevocationList{
+		V:           1,
+		Type:        imodels.RevocationListType,
+		OrgID:       orgID,
+		Version:     version,
+		Since:       since,
+		IssuedAt:    now,
+		Entries:     make([]imodels.RevocationEntry, 0, len(entries)),
+		IssuerKeyID: kid,
+	}
+	for _, e := range entries {
+		body.Entries = append(body.Entries, imodels.RevocationEntry{
+			PassID:    e.PassID,
+			EXP:       e.EXP.UTC(),
+			RevokedAt: e.RevokedAt.UTC(),
+			Reason:    imodels.RevocationReason(e.Reason),
+			Seq:       e.Seq,
+		})
+	}
+	payloadB, err := json.Marshal(body)
+	if err != nil {
+		return RevocationListResult{}, err
+	}
+	compact, _, err := s.signer.SignJWS(kid, priv, payloadB)
+	if err != nil {
+		return RevocationListResult{}, err
+	}
+	return RevocationListResult{
+		Version:     version,
+		Since:       since,
+		Count:       len(body.Entries),
+		IssuerKeyID: kid,
+		Payload:     compact,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,47 +15,174 @@ import (
 
 // Service реализует use case'ы выпуска
 type Service struct {
-	keys   KeyRepository
-	passes PassRepository
-	clock  Clock
-	signer Signer
+	keys     KeyRepository
+	passes   PassRepository
+	zones    ZoneRepository
+	policies PolicyRepository
+	idem     IdempotencyRepository
+	clock    Clock
+	signer   Signer
+	verifier Verifier
+	opts     Options
+	schemas  *schemaCache
 }
 
-func New(keys KeyRepository, passes PassRepository, clock Clock, signer Signer) *Service {
-	return &Service{keys: keys, passes: passes, clock: clock, signer: signer}
+func New(keys KeyRepository, passes PassRepository, zones ZoneRepository, policies PolicyRepository, idem IdempotencyRepository, clock Clock, signer Signer, verifier Verifier, opts Options) *Service {
+	return &Service{keys: keys, passes: passes, zones: zones, policies: policies, idem: idem, clock: clock, signer: signer, verifier: verifier, opts: opts, schemas: newSchemaCache()}
 }
 
 // ошибки вынесены в errors.go
 
-// IssuePass — основной сценарий выпуска
-func (s *Service) IssuePass(ctx context.Context, cmd IssuePassCommand) (IssuePassResult, error) {
+// signingKey — активный ключ эмитента; подписываем только EdDSA
+func (s *Service) signingKey(ctx context.Context) (kid string, priv []byte, err error) {
 	kid, alg, _, priv, err := s.keys.GetActiveIssuerKey(ctx)
 	if err != nil {
-		return IssuePassResult{}, err
+		return "", nil, err
 	}
 	if alg != "EdDSA" {
-		return IssuePassResult{}, ErrUnsupportedAlg
+		return "", nil, ErrUnsupportedAlg
+	}
+	return kid, priv, nil
+}
+
+// IssuePass — основной сценарий выпуска: политика задаёт level, one_time по умолчанию и
+// ограничения; все нарушения возвращаются разом в PolicyViolationError
+func (s *Service) IssuePass(ctx context.Context, cmd IssuePassCommand) (IssuePassResult, error) {
+	return s.issue(ctx, cmd, s.passes.InsertPass)
+}
+
+// issue — проверка, подпись и сохранение пропуска через insert
+func (s *Service) issue(ctx context.Context, cmd IssuePassCommand, insert func(context.Context, PassRecord) error) (IssuePassResult, error) {
+	d, err := s.prepareIssue(ctx, cmd, s.policies.GetPolicy)
+	if err != nil {
+		return IssuePassResult{}, err
+	}
+	kid, priv, err := s.signingKey(ctx)
+	if err != nil {
+		return IssuePassResult{}, err
+	}
+
+	statusIndex, err := s.passes.NextStatusIndex(ctx)
+	if err != nil {
+		return IssuePassResult{}, err
 	}
+	rec, err := s.signIssue(d, kid, priv, statusIndex)
+	if err != nil {
+		return IssuePassResult{}, err
+	}
+	if err := insert(ctx, rec); err != nil {
+		return IssuePassResult{}, err
+	}
+	return issueResult(rec), nil
+}
+
+// issueDraft — команда выпуска, прошедшая проверки: зоны разрешены, level и one_time взяты из политики
+type issueDraft struct {
+	cmd     IssuePassCommand
+	level   string
+	oneTime bool
+}
+
+// MaxZones — предел числа зон одного пропуска
+const MaxZones = 64
+
+// Validate проверяет инварианты команды выпуска, не зависящие от реестров: от 1 до MaxZones
+// зон, nbf раньше exp, max_uses не противоречит one_time, расписание корректно.
+// Общая проверка для POST /passes, пакетного выпуска и импорта списков.
+func (c IssuePassCommand) Validate() error {
+	if len(c.ZoneIDs) == 0 {
+		return ErrNoZones
+	}
+	if len(c.ZoneIDs) > MaxZones {
+		return ErrTooManyZones
+	}
+	if !c.NBF.Before(c.EXP) {
+		return ErrNbfAfterExp
+	}
+	// one_time эквивалентен max_uses=1
+	if c.MaxUses < 0 || (c.OneTime != nil && *c.OneTime && c.MaxUses > 1) {
+		return ErrInvalidMaxUses
+	}
+	if c.Schedule != nil {
+		return c.Schedule.Validate()
+	}
+	return nil
+}
+
+// policyGetter — источник политик; пакетный выпуск подставляет кеширующий
+type policyGetter func(ctx context.Context, orgID, id string) (Policy, error)
 
+// prepareIssue — проверки команды выпуска и применение политики; ничего не пишет
+func (s *Service) prepareIssue(ctx context.Context, cmd IssuePassCommand, getPolicy policyGetter) (issueDraft, error) {
+	if len(cmd.ZoneIDs) == 0 {
+		return issueDraft{}, ErrNoZones
+	}
+	if cmd.Schedule != nil {
+		if err := cmd.Schedule.Validate(); err != nil {
+			return issueDraft{}, err
+		}
+	}
+	policy, err := s.lookupPolicy(ctx, getPolicy, cmd.OrgID, cmd.PolicyID)
+	if err != nil {
+		return issueDraft{}, err
+	}
+	if len(policy.Issuers) > 0 && !slices.Contains(policy.Issuers, cmd.Actor) {
+		return issueDraft{}, ErrIssuerDenied
+	}
+	zoneIDs, err := s.resolveZones(ctx, cmd.OrgID, cmd.ZoneIDs, cmd.ExpandZones)
+	if err != nil {
+		return issueDraft{}, err
+	}
+	cmd.ZoneIDs = zoneIDs
+	violations, err := s.checkPolicy(ctx, policy, cmd)
+	if err != nil {
+		return issueDraft{}, err
+	}
+	if len(violations) > 0 {
+		return issueDraft{}, &PolicyViolationError{PolicyID: policy.ID, Violations: violations}
+	}
+	// явный max_uses>1 означает многоразовый пропуск и отменяет default_one_time
+	oneTime := policy.DefaultOneTime && cmd.MaxUses <= 1
+	if cmd.OneTime != nil {
+		oneTime = *cmd.OneTime
+	}
+	return issueDraft{cmd: cmd, level: policy.Level, oneTime: oneTime}, nil
+}
+
+// signIssue — payload нового пропуска, подписанный ключом kid, и запись для сохранения
+func (s *Service) signIssue(d issueDraft, kid string, priv []byte, statusIndex int64) (PassRecord, error) {
+	cmd := d.cmd
 	passID := uuid.New().String()
 	nonce := make([]byte, 12)
 	if _, err := rand.Read(nonce); err != nil {
-		return IssuePassResult{}, err
+		return PassRecord{}, err
 	}
 	holderHint := util.HolderHintFromName(cmd.SubjectName)
+	maxUses := cmd.MaxUses
+	if d.oneTime {
+		maxUses = 1
+	}
 
 	body := imodels.SignedPayload{
 		V: 1,
 		Pass: imodels.PayloadPass{
 			ID:         passID,
+			Version:    1,
 			Type:       cmd.PolicyID,
-			Level:      "",
-			Scopes:     []string{cmd.ZoneID},
-			OneTime:    cmd.OneTime,
+			Level:      d.level,
+			Scopes:     cmd.ZoneIDs,
+			OneTime:    d.oneTime,
+			MaxUses:    maxUses,
 			NBF:        cmd.NBF.UTC(),
 			EXP:        cmd.EXP.UTC(),
+			Schedule:   cmd.Schedule,
 			Attrs:      cmd.Attrs,
 			HolderHint: holderHint,
+			Status: &imodels.PayloadStatus{
+				Purpose: imodels.StatusPurposeRevocation,
+				List:    s.opts.StatusListURL,
+				Index:   statusIndex,
+			},
 		},
 		Meta: imodels.PayloadMeta{
 			OrgID:         cmd.OrgID,
@@ -68,36 +196,282 @@ func (s *Service) IssuePass(ctx context.Context, cmd IssuePassCommand) (IssuePas
 	}
 	payloadB, err := json.Marshal(body)
 	if err != nil {
-		return IssuePassResult{}, err
+		return PassRecord{}, err
 	}
 
 	compact, sig, err := s.signer.SignJWS(kid, priv, payloadB)
 	if err != nil {
-		return IssuePassResult{}, err
+		return PassRecord{}, err
 	}
 
-	rec := PassRecord{
+	return PassRecord{
 		ID:          passID,
 		OrgID:       cmd.OrgID,
 		PolicyID:    cmd.PolicyID,
 		SubjectName: cmd.SubjectName,
-		ZoneID:      cmd.ZoneID,
+		ZoneID:      cmd.ZoneIDs[0],
+		ZoneIDs:     cmd.ZoneIDs,
 		NBF:         cmd.NBF.UTC(),
 		EXP:         cmd.EXP.UTC(),
-		OneTime:     cmd.OneTime,
+		OneTime:     d.oneTime,
+		MaxUses:     maxUses,
+		Schedule:    cmd.Schedule,
+		Version:     1,
 		IssuerKeyID: kid,
+		StatusIndex: statusIndex,
 		Signature:   sig,
 		Payload:     []byte(compact),
+	}, nil
+}
+
+func issueResult(rec PassRecord) IssuePassResult {
+	return IssuePassResult{ID: rec.ID, IssuerKeyID: rec.IssuerKeyID, Payload: string(rec.Payload)}
+}
+
+type RevokePassCommand struct {
+	ID     string
+	Reason imodels.RevocationReason
+	Note   string
+	Actor  string
+}
+
+// RevokePass — смена статуса на Revoked с фиксацией причины и инициатора
+func (s *Service) RevokePass(ctx context.Context, cmd RevokePassCommand) error {
+	if cmd.Reason == "" {
+		cmd.Reason = imodels.RevocationOther
 	}
-	if err := s.passes.InsertPass(ctx, rec); err != nil {
-		return IssuePassResult{}, err
+	if !cmd.Reason.Valid() {
+		return ErrInvalidReason
 	}
-	return IssuePassResult{ID: passID, IssuerKeyID: kid, Payload: compact}, nil
+	return s.passes.RevokeActivePass(ctx, cmd.ID, RevocationMeta{Reason: cmd.Reason, Note: cmd.Note, Actor: cmd.Actor})
+}
+
+// GetPass — текущее состояние пропуска вместе с выданным JWS
+func (s *Service) GetPass(ctx context.Context, id string) (PassView, error) {
+	return s.passes.GetPass(ctx, id)
 }
 
-// RevokePass — смена статуса на Revoked
-func (s *Service) RevokePass(ctx context.Context, id string) error {
-	return s.passes.RevokeActivePass(ctx, id)
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// ExportPasses — все пропуска по фильтру в порядке выпуска, по одному в fn, без загрузки
+// выборки в память; ошибка fn прерывает выгрузку и возвращается
+func (s *Service) ExportPasses(ctx context.Context, f PassFilter, fn func(PassView) error) error {
+	return s.passes.ExportPasses(ctx, f, fn)
+}
+
+// ListPasses — страница пропусков по фильтру с keyset-пагинацией
+func (s *Service) ListPasses(ctx context.Context, q ListPassesQuery) (ListPassesResult, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	var after *PassCursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return ListPassesResult{}, err
+		}
+		after = c
+	}
+	// берём на одну запись больше, чтобы понять, есть ли следующая страница
+	items, err := s.passes.ListPasses(ctx, q.Filter, after, limit+1)
+	if err != nil {
+		return ListPassesResult{}, err
+	}
+	res := ListPassesResult{Items: items}
+	if len(items) > limit {
+		res.Items = items[:limit]
+		last := res.Items[limit-1]
+		next, err := encodeCursor(PassCursor{EXP: last.EXP.UTC(), ID: last.ID})
+		if err != nil {
+			return ListPassesResult{}, err
+		}
+		res.NextCursor = next
+	}
+	return res, nil
+}
+
+type RenewPassCommand struct {
+	ID    string
+	EXP   time.Time
+	Actor string
+}
+
+// RenewPassResult — новый пропуск, заменивший ReplacesID
+type RenewPassResult struct {
+	ID          string
+	ReplacesID  string
+	IssuerKeyID string
+	EXP         time.Time
+	Payload     string
+}
+
+// RenewPass — продление: новый JWS с тем же содержимым и новым exp подписывается активным ключом
+// (meta.replaces — старый id), старый пропуск отзывается с причиной superseded. Остаток проходов
+// переносится; продлить можно только Active пропуск, только в большую сторону и в пределах TTL политики.
+func (s *Service) RenewPass(ctx context.Context, cmd RenewPassCommand) (RenewPassResult, error) {
+	kid, priv, err := s.signingKey(ctx)
+	if err != nil {
+		return RenewPassResult{}, err
+	}
+	exp := cmd.EXP.UTC()
+	build := func(p PassView) (*PassRecord, error) {
+		if !exp.After(p.EXP) {
+			return nil, ErrNotExtended
+		}
+		limit, err := s.ttlLimit(ctx, p.OrgID, p.PolicyID)
+		if err != nil {
+			return nil, err
+		}
+		if exp.Sub(s.clock.Now()) > limit {
+			return nil, &PolicyViolationError{PolicyID: p.PolicyID, Violations: []Violation{ttlViolation(limit)}}
+		}
+		if p.MaxUses > 0 && p.Uses >= p.MaxUses {
+			return nil, ErrExhausted
+		}
+		return s.resign(ctx, p, kid, priv, func(body *imodels.SignedPayload) {
+			body.Pass.EXP = exp
+			if p.MaxUses > 0 {
+				body.Pass.MaxUses = p.MaxUses - p.Uses
+			}
+		})
+	}
+	meta := RevocationMeta{Reason: imodels.RevocationSuperseded, Actor: cmd.Actor}
+	rec, err := s.passes.SupersedePass(ctx, cmd.ID, meta, build)
+	if err != nil {
+		return RenewPassResult{}, err
+	}
+	return RenewPassResult{
+		ID:          rec.ID,
+		ReplacesID:  cmd.ID,
+		IssuerKeyID: rec.IssuerKeyID,
+		EXP:         rec.EXP,
+		Payload:     string(rec.Payload),
+	}, nil
+}
+
+// AmendPassCommand — изменения пропуска; nil — поле не меняется, пустой Attrs очищает атрибуты
+type AmendPassCommand struct {
+	ID          string
+	ZoneIDs     []string
+	ExpandZones bool
+	SubjectName *string
+	Attrs       map[string]any
+	Actor       string
+}
+
+type AmendPassResult struct {
+	ID          string
+	Version     int
+	IssuerKeyID string
+	Payload     string
+}
+
+// AmendPass — новая подписанная версия пропуска под тем же id: меняются зона, атрибуты и/или
+// владелец (holder_hint), payload получает version+1 и новый индекс в списке статусов.
+// Прежняя версия уходит в pass_versions, verify отвечает на неё superseded, а её индекс
+// помечается в списке статусов как отозванный.
+func (s *Service) AmendPass(ctx context.Context, cmd AmendPassCommand) (AmendPassResult, error) {
+	if cmd.ZoneIDs == nil && cmd.SubjectName == nil && cmd.Attrs == nil {
+		return AmendPassResult{}, ErrNothingToAmend
+	}
+	if cmd.ZoneIDs != nil && len(cmd.ZoneIDs) == 0 {
+		return AmendPassResult{}, ErrNoZones
+	}
+	kid, priv, err := s.signingKey(ctx)
+	if err != nil {
+		return AmendPassResult{}, err
+	}
+	build := func(p PassView) (*PassRecord, error) {
+		if cmd.Attrs != nil {
+			if err := s.checkAmendedAttrs(ctx, p.OrgID, p.PolicyID, cmd.Attrs); err != nil {
+				return nil, err
+			}
+		}
+		var zoneIDs []string
+		if cmd.ZoneIDs != nil {
+			resolved, err := s.resolveZones(ctx, p.OrgID, cmd.ZoneIDs, cmd.ExpandZones)
+			if err != nil {
+				return nil, err
+			}
+			zoneIDs = resolved
+		}
+		return s.resignPayload(ctx, p, kid, priv, func(body *imodels.SignedPayload, rec *PassRecord) {
+			body.Pass.Version = max(p.Version, 1) + 1
+			if zoneIDs != nil {
+				body.Pass.Scopes = zoneIDs
+				rec.ZoneID = zoneIDs[0]
+				rec.ZoneIDs = zoneIDs
+			}
+			if cmd.SubjectName != nil {
+				body.Pass.HolderHint = util.HolderHintFromName(*cmd.SubjectName)
+				rec.SubjectName = *cmd.SubjectName
+			}
+			if cmd.Attrs != nil {
+				body.Pass.Attrs = cmd.Attrs
+			}
+		})
+	}
+	rec, err := s.passes.AmendPass(ctx, cmd.ID, cmd.Actor, build)
+	if err != nil {
+		return AmendPassResult{}, err
+	}
+	return AmendPassResult{ID: rec.ID, Version: rec.Version, IssuerKeyID: rec.IssuerKeyID, Payload: string(rec.Payload)}, nil
+}
+
+type BulkRevokeCommand struct {
+	Filter BulkRevokeFilter
+	DryRun bool
+	Reason imodels.RevocationReason
+	Note   string
+	Actor  string
+}
+
+// BulkRevokeResult — при DryRun Revoked=0, IDs — что было бы отозвано
+type BulkRevokeResult struct {
+	DryRun  bool
+	Matched int
+	Revoked int
+	IDs     []string
+}
+
+// BulkRevokePasses — отзыв всех Active/Suspended пропусков по фильтру одной транзакцией
+func (s *Service) BulkRevokePasses(ctx context.Context, cmd BulkRevokeCommand) (BulkRevokeResult, error) {
+	if cmd.Filter.IsEmpty() {
+		return BulkRevokeResult{}, ErrEmptyFilter
+	}
+	if cmd.Reason == "" {
+		cmd.Reason = imodels.RevocationOther
+	}
+	if !cmd.Reason.Valid() {
+		return BulkRevokeResult{}, ErrInvalidReason
+	}
+	meta := RevocationMeta{Reason: cmd.Reason, Note: cmd.Note, Actor: cmd.Actor}
+	ids, err := s.passes.BulkRevoke(ctx, cmd.Filter, meta, cmd.DryRun)
+	if err != nil {
+		return BulkRevokeResult{}, err
+	}
+	res := BulkRevokeResult{DryRun: cmd.DryRun, Matched: len(ids), IDs: ids}
+	if !cmd.DryRun {
+		res.Revoked = len(ids)
+	}
+	return res, nil
+}
+
+// SuspendPass — временная блокировка: Active → Suspended
+func (s *Service) SuspendPass(ctx context.Context, id, actor string) error {
+	return s.passes.SuspendActivePass(ctx, id, actor)
+}
+
+// ReinstatePass — снятие блокировки: Suspended → Active; истёкший пропуск не восстанавливается
+func (s *Service) ReinstatePass(ctx context.Context, id string) error {
+	return s.passes.ReinstateSuspendedPass(ctx, id, s.clock.Now().UTC())
 }
 
 type ApproveResult struct {
@@ -107,11 +481,12 @@ type ApproveResult struct {
 
 // ApprovePass — генерирует pickup-token
 func (s *Service) ApprovePass(ctx context.Context, id string, ttl time.Duration) (ApproveResult, error) {
-	st, err := s.passes.GetPassStatus(ctx, id)
+	p, err := s.passes.GetPass(ctx, id)
 	if err != nil {
 		return ApproveResult{}, err
 	}
-	if st != string(imodels.StatusActive) {
+	// просроченный пропуск может ещё не дождаться sweeper'а — проверяем exp явно
+	if p.Status != string(imodels.StatusActive) || !p.EXP.After(s.clock.Now()) {
 		return ApproveResult{}, ErrConflict
 	}
 	// генерируем токен
@@ -141,6 +516,82 @@ func (s *Service) Pickup(ctx context.Context, token string) (PickupResult, error
 	return PickupResult{Payload: string(payload), IssuerKeyID: kid}, nil
 }
 
+type RedeemCommand struct {
+	PassID   string
+	ReaderID string
+	ZoneID   string
+}
+
+// RedeemResult — итог прохода; Remaining=nil у пропусков без лимита
+type RedeemResult struct {
+	PassID     string
+	Uses       int
+	Remaining  *int
+	RedeemedAt time.Time
+}
+
+// RedeemPass — атомарно фиксирует проход и уменьшает остаток использований;
+// одноразовый пропуск гасится только один раз, многоразовый — не более max_uses
+func (s *Service) RedeemPass(ctx context.Context, cmd RedeemCommand) (RedeemResult, error) {
+	now := s.clock.Now().UTC()
+	rec := RedemptionRecord{PassID: cmd.PassID, ReaderID: cmd.ReaderID, ZoneID: cmd.ZoneID, At: now}
+	p, err := s.passes.RedeemPass(ctx, rec, func(p PassView) error {
+		if p.Status != string(imodels.StatusActive) {
+			return ErrConflict
+		}
+		if now.Before(p.NBF) || !now.Before(p.EXP) {
+			return ErrOutsideValidity
+		}
+		if !p.Schedule.Allows(now) {
+			return ErrOutsideSchedule
+		}
+		if !slices.Contains(p.ZoneIDs, cmd.ZoneID) {
+			return ErrZoneNotAllowed
+		}
+		if (p.OneTime || p.MaxUses == 1) && p.Uses > 0 {
+			return ErrAlreadyRedeemed
+		}
+		if p.MaxUses > 0 && p.Uses >= p.MaxUses {
+			return ErrExhausted
+		}
+		return nil
+	})
+	if err != nil {
+		return RedeemResult{}, err
+	}
+	res := RedeemResult{PassID: p.ID, Uses: p.Uses, RedeemedAt: now}
+	if p.MaxUses > 0 {
+		left := p.MaxUses - p.Uses
+		res.Remaining = &left
+	}
+	return res, nil
+}
+
+type SweepResult struct {
+	ExpiredPasses  int64
+	PurgedTokens   int64
+	PurgedIdemKeys int64
+}
+
+// SweepExpired — переводит просроченные пропуска в Expired и чистит отработавшие pickup-токены
+// и истёкшие ключи идемпотентности
+func (s *Service) SweepExpired(ctx context.Context) (SweepResult, error) {
+	now := s.clock.Now().UTC()
+	expired, err := s.passes.ExpireOverduePasses(ctx, now)
+	if err != nil {
+		return SweepResult{}, err
+	}
+	purged, err := s.passes.PurgePickupTokens(ctx, now)
+	if err != nil {
+		return SweepResult{ExpiredPasses: expired}, err
+	}
+	keys, err := s.idem.PurgeIdempotencyKeys(ctx, now)
+	if err != nil {
+		return SweepResult{ExpiredPasses: expired, PurgedTokens: purged}, err
+	}
+	return SweepResult{ExpiredPasses: expired, PurgedTokens: purged, PurgedIdemKeys: keys}, nil
+}
+
 // ListIssuerKeys — список ключей эмитента для JWKS
 func (s *Service) ListIssuerKeys(ctx context.Context) ([]IssuerKey, error) {
 	return s.keys.ListIssuerKeys(ctx)
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	imodels "github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+type StatusListResult struct {
+	IssuerKeyID string
+	Payload     string
+}
+
+// GetStatusList — подписанная сжатая битовая строка статусов; бит pass.status.index = 1 у отозванных
+// и приостановленных пропусков, reinstate снова его сбрасывает.
+// Считыватель скачивает список целиком и не раскрывает, какой пропуск проверяет.
+func (s *Service) GetStatusList(ctx context.Context) (StatusListResult, error) {
+	kid, priv, err := s.signingKey(ctx)
+	if err != nil {
+		return StatusListResult{}, err
+	}
+	blocked, maxIndex, err := s.passes.ListBlockedStatusIndexes(ctx)
+	if err != nil {
+		return StatusListResult{}, err
+	}
+	bits := imodels.NewStatusBits(maxIndex)
+	for _, idx := range blocked {
+		if err := imodels.SetStatusBit(bits, idx); err != nil {
+			return StatusListResult{}, err
+		}
+	}
+	encoded, err := imodels.EncodeStatusBits(bits)
+	if err != nil {
+		return StatusListResult{}, err
+	}
+	body := imodels.StatusList{
+		V:           1,
+		Type:        imodels.StatusListType,
+		ID:          s.opts.StatusListURL,
+		Purpose:     imodels.StatusPurposeRevocation,
+		Size:        int64(len(bits)) * 8,
+		EncodedList: encoded,
+		IssuedAt:    s.clock.Now().UTC(),
+		IssuerKeyID: kid,
+	}
+	payloadB, err := json.Marshal(body)
+	if err != nil {
+		return StatusListResult{}, err
+	}
+	compact, _, err := s.signer.SignJWS(kid, priv, payloadB)
+	if err != nil {
+		return StatusListResult{}, err
+	}
+	return StatusListResult{IssuerKeyID: kid, Payload: compact}, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	imodels "github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+// VerifyReason — код вердикта проверки пропуска
+type VerifyReason string
+
+const (
+	ReasonOK              VerifyReason = "ok"
+	ReasonMalformed       VerifyReason = "malformed"
+	ReasonUnsupportedAlg  VerifyReason = "unsupported_alg"
+	ReasonUnknownKey      VerifyReason = "unknown_key"
+	ReasonBadSignature    VerifyReason = "bad_signature"
+	ReasonKeyMismatch     VerifyReason = "key_mismatch"
+	ReasonUnknownPass     VerifyReason = "unknown_pass"
+	ReasonPayloadMismatch VerifyReason = "payload_mismatch"
+	ReasonSuperseded      VerifyReason = "superseded"
+	ReasonRevoked         VerifyReason = "revoked"
+	ReasonSuspended       VerifyReason = "suspended"
+	ReasonExpired         VerifyReason = "expired"
+	ReasonExhausted       VerifyReason = "exhausted"
+	ReasonNotYetValid     VerifyReason = "not_yet_valid"
+	ReasonOutsideSchedule VerifyReason = "outside_schedule"
+)
+
+// VerifyResult — вердикт проверки; поля пропуска заполнены, если payload удалось разобрать
+type VerifyResult struct {
+	Valid       bool
+	Reason      VerifyReason
+	PassID      string
+	IssuerKeyID string
+	Status      string
+	Scopes      []string
+	NBF         time.Time
+	EXP         time.Time
+}
+
+func rejected(res VerifyResult, reason VerifyReason) (VerifyResult, error) {
+	res.Valid = false
+	res.Reason = reason
+	return res, nil
+}
+
+// VerifyPass — авторитетная проверка compact JWS: подпись, статус в БД, окно nbf/exp с допуском skew
+// и окна расписания.
+// Ошибка возвращается только при сбое инфраструктуры; невалидный пропуск — это вердикт.
+func (s *Service) VerifyPass(ctx context.Context, compact string, skew time.Duration) (VerifyResult, error) {
+	var res VerifyResult
+	kid, alg, payloadB, err := s.verifier.ParseJWS(compact)
+	if err != nil {
+		return rejected(res, ReasonMalformed)
+	}
+	res.IssuerKeyID = kid
+	if alg != "EdDSA" {
+		return rejected(res, ReasonUnsupportedAlg)
+	}
+
+	key, err := s.keys.GetIssuerKey(ctx, kid)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return rejected(res, ReasonUnknownKey)
+		}
+		return VerifyResult{}, err
+	}
+	if key.Alg != alg {
+		return rejected(res, ReasonUnsupportedAlg)
+	}
+	if err := s.verifier.VerifyJWS(compact, key.PublicKey); err != nil {
+		return rejected(res, ReasonBadSignature)
+	}
+
+	var body imodels.SignedPayload
+	if err := json.Unmarshal(payloadB, &body); err != nil || body.Pass.ID == "" {
+		return rejected(res, ReasonMalformed)
+	}
+	res.PassID = body.Pass.ID
+	res.Scopes = body.Pass.Scopes
+	res.NBF = body.Pass.NBF
+	res.EXP = body.Pass.EXP
+	if body.IssuerKeyID != kid {
+		return rejected(res, ReasonKeyMismatch)
+	}
+
+	p, err := s.passes.GetPass(ctx, body.Pass.ID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return rejected(res, ReasonUnknownPass)
+		}
+		return VerifyResult{}, err
+	}
+	res.Status = p.Status
+	// пропуск изменён через PATCH: на руках устаревшая версия
+	if max(body.Pass.Version, 1) < p.Version {
+		return rejected(res, ReasonSuperseded)
+	}
+	// подпись валидна, но выдавали мы другой JWS — значит, payload подменён или перевыпущен
+	if p.Payload != compact {
+		return rejected(res, ReasonPayloadMismatch)
+	}
+	switch imodels.PassStatus(p.Status) {
+	case imodels.StatusRevoked:
+		return rejected(res, ReasonRevoked)
+	case imodels.StatusSuspended:
+		return rejected(res, ReasonSuspended)
+	case imodels.StatusExpired:
+		return rejected(res, ReasonExpired)
+	}
+	if p.MaxUses > 0 && p.Uses >= p.MaxUses {
+		return rejected(res, ReasonExhausted)
+	}
+
+	now := s.clock.Now().UTC()
+	if now.Add(skew).Before(body.Pass.NBF) {
+		return rejected(res, ReasonNotYetValid)
+	}
+	if !now.Add(-skew).Before(body.Pass.EXP) {
+		return rejected(res, ReasonExpired)
+	}
+	// окна расписания проверяются без допуска: skew компенсирует часы, а не границы смены
+	if !body.Pass.Schedule.Allows(now) {
+		return rejected(res, ReasonOutsideSchedule)
+	}
+	res.Valid = true
+	res.Reason = ReasonOK
+	return res, nil
+}
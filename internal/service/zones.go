@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// CreateZone — новая зона; родитель обязателен для этажа и комнаты и должен быть
+// уровнем выше в той же организации, у здания родителя нет
+func (s *Service) CreateZone(ctx context.Context, z Zone) (Zone, error) {
+	if err := s.checkParent(ctx, z); err != nil {
+		return Zone{}, err
+	}
+	if err := s.zones.InsertZone(ctx, z); err != nil {
+		return Zone{}, err
+	}
+	return s.zones.GetZone(ctx, z.OrgID, z.ID)
+}
+
+// GetZone — зона организации по id
+func (s *Service) GetZone(ctx context.Context, orgID, id string) (Zone, error) {
+	return s.zones.GetZone(ctx, orgID, id)
+}
+
+// ListZones — зоны организации; с parentID — только прямые потомки
+func (s *Service) ListZones(ctx context.Context, orgID, parentID string) ([]Zone, error) {
+	return s.zones.ListZones(ctx, orgID, parentID)
+}
+
+// UpdateZoneCommand — nil-поля не меняются; уровень и организация зоны неизменны
+type UpdateZoneCommand struct {
+	OrgID    string
+	ID       string
+	Name     *string
+	ParentID *string
+}
+
+// UpdateZone — переименование и/или перенос под другого родителя того же уровня
+func (s *Service) UpdateZone(ctx context.Context, cmd UpdateZoneCommand) (Zone, error) {
+	z, err := s.zones.GetZone(ctx, cmd.OrgID, cmd.ID)
+	if err != nil {
+		return Zone{}, err
+	}
+	if cmd.Name != nil {
+		z.Name = *cmd.Name
+	}
+	if cmd.ParentID != nil {
+		z.ParentID = *cmd.ParentID
+		if err := s.checkParent(ctx, z); err != nil {
+			return Zone{}, err
+		}
+	}
+	if err := s.zones.UpdateZone(ctx, z); err != nil {
+		return Zone{}, err
+	}
+	return s.zones.GetZone(ctx, z.OrgID, z.ID)
+}
+
+// DeleteZone — удаление зоны организации без дочерних зон и без действующих пропусков
+func (s *Service) DeleteZone(ctx context.Context, orgID, id string) error {
+	return s.zones.DeleteZone(ctx, orgID, id)
+}
+
+// checkParent — родитель соответствует иерархии building > floor > room
+func (s *Service) checkParent(ctx context.Context, z Zone) error {
+	want := z.Kind.ParentKind()
+	if want == "" {
+		if z.ParentID != "" {
+			return ErrInvalidParent
+		}
+		return nil
+	}
+	if z.ParentID == "" {
+		return ErrInvalidParent
+	}
+	parent, err := s.zones.GetZone(ctx, z.OrgID, z.ParentID)
+	if err != nil {
+		if errors.Is(err, ErrZoneNotFound) {
+			return ErrInvalidParent
+		}
+		return err
+	}
+	if parent.Kind != want {
+		return ErrInvalidParent
+	}
+	return nil
+}
+
+// resolveZones — проверяет, что все зоны есть в реестре организации; с expand добавляет
+// после запрошенных всех их потомков; итог, как и в Validate, — не больше MaxZones
+func (s *Service) resolveZones(ctx context.Context, orgID string, ids []string, expand bool) ([]string, error) {
+	found, err := s.zones.ResolveZones(ctx, orgID, ids, expand)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, id := range ids {
+		if !slices.Contains(found, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &UnknownZonesError{IDs: missing}
+	}
+	if !expand {
+		return ids, nil
+	}
+	var extra []string
+	for _, id := range found {
+		if !slices.Contains(ids, id) {
+			extra = append(extra, id)
+		}
+	}
+	slices.Sort(extra)
+	result := append(slices.Clone(ids), extra...)
+	if len(result) > MaxZones {
+		return nil, ErrTooManyZones
+	}
+	return result, nil
+}
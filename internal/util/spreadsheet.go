@@ -0,0 +1,12 @@
+package util
+
+import "strings"
+
+// SpreadsheetSafe — значение CSV-ячейки, которое Excel не примет за формулу: ведущие
+// =, +, -, @, табуляция и возврат каретки экранируются апострофом
+func SpreadsheetSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
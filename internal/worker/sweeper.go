@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	issvc "github.com/vbncursed/vkr/issue-service/internal/service"
+)
+
+// Sweeper периодически вызывает Service.SweepExpired до отмены контекста
+type Sweeper struct {
+	svc      *issvc.Service
+	interval time.Duration
+}
+
+func NewSweeper(svc *issvc.Service, interval time.Duration) *Sweeper {
+	return &Sweeper{svc: svc, interval: interval}
+}
+
+// Run блокируется до отмены ctx; первый проход выполняется сразу при старте
+func (w *Sweeper) Run(ctx context.Context) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		w.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (w *Sweeper) sweep(ctx context.Context) {
+	res, err := w.svc.SweepExpired(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("sweeper: %v", err)
+		}
+		return
+	}
+	if res.ExpiredPasses > 0 || res.PurgedTokens > 0 || res.PurgedIdemKeys > 0 {
+		log.Printf("sweeper: expired=%d purged_tokens=%d purged_idempotency_keys=%d", res.ExpiredPasses, res.PurgedTokens, res.PurgedIdemKeys)
+	}
+}
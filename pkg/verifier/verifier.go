@@ -0,0 +1,184 @@
+// Package verifier — офлайн-проверка пропусков, выпущенных issue-service.
+//
+// Разбирает compact JWS (EdDSA), проверяет подпись по JWKS из /.well-known/keys,
+// окно nbf/exp, окна расписания и зону прохода. Отзыв проверяется по скачанному списку статусов
+// (VerifyStatusList) либо онлайн через POST /api/v1/verify.
+package verifier
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/vbncursed/vkr/issue-service/internal/crypto"
+	"github.com/vbncursed/vkr/issue-service/internal/models"
+)
+
+// Публичные псевдонимы внутренних типов: читатели используют их вместо копий
+type (
+	SignedPayload = models.SignedPayload
+	PayloadPass   = models.PayloadPass
+	PayloadMeta   = models.PayloadMeta
+	PayloadStatus = models.PayloadStatus
+	Schedule      = models.Schedule
+	StatusList    = models.StatusList
+	JWK           = models.JWK
+	JWKSet        = models.JWKSet
+)
+
+var (
+	ErrNoKeys          = errors.New("verifier: no usable keys")
+	ErrMalformed       = errors.New("verifier: malformed")
+	ErrUnsupportedAlg  = errors.New("verifier: unsupported alg")
+	ErrUnknownKey      = errors.New("verifier: unknown key")
+	ErrBadSignature    = errors.New("verifier: bad signature")
+	ErrKeyMismatch     = errors.New("verifier: issuer_key_id does not match kid")
+	ErrNotYetValid     = errors.New("verifier: not yet valid")
+	ErrExpired         = errors.New("verifier: expired")
+	ErrOutsideSchedule = errors.New("verifier: outside schedule")
+	ErrScopeNotAllowed = errors.New("verifier: scope not allowed")
+	ErrNoStatus        = errors.New("verifier: pass has no status entry")
+	ErrStatusMismatch  = errors.New("verifier: status list does not match pass")
+)
+
+// Options — параметры проверки
+type Options struct {
+	// Skew — допуск рассинхронизации часов для nbf/exp
+	Skew time.Duration
+	// Now — источник времени; по умолчанию time.Now
+	Now func() time.Time
+}
+
+// Verifier проверяет пропуска по фиксированному набору ключей; безопасен для конкурентного использования
+type Verifier struct {
+	keys map[string]ed25519.PublicKey
+	opts Options
+}
+
+// ParseJWKSet читает JWKS в формате /.well-known/keys
+func ParseJWKSet(r io.Reader) (JWKSet, error) {
+	var set JWKSet
+	if err := json.NewDecoder(r).Decode(&set); err != nil {
+		return JWKSet{}, err
+	}
+	return set, nil
+}
+
+// New строит Verifier из JWKS; ключи с другим kty/crv/alg пропускаются
+func New(set JWKSet, opts Options) (*Verifier, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "OKP" || k.Crv != "Ed25519" || k.Alg != "EdDSA" || k.Kid == "" {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			continue
+		}
+		keys[k.Kid] = ed25519.PublicKey(x)
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return &Verifier{keys: keys, opts: opts}, nil
+}
+
+// Verify проверяет подпись, окно действия и расписание пропуска и возвращает разобранный payload.
+// Если zone не пустая, она должна входить в pass.scopes. При отказах после проверки подписи
+// payload возвращается вместе с ошибкой — он подлинный и пригоден для журнала.
+func (v *Verifier) Verify(compact string, zone string) (*SignedPayload, error) {
+	kid, payloadB, err := v.verifySignature(compact)
+	if err != nil {
+		return nil, err
+	}
+	var body SignedPayload
+	if err := json.Unmarshal(payloadB, &body); err != nil || body.Pass.ID == "" {
+		return nil, ErrMalformed
+	}
+	if body.IssuerKeyID != kid {
+		return &body, ErrKeyMismatch
+	}
+
+	now := v.opts.Now().UTC()
+	if now.Add(v.opts.Skew).Before(body.Pass.NBF) {
+		return &body, ErrNotYetValid
+	}
+	if !now.Add(-v.opts.Skew).Before(body.Pass.EXP) {
+		return &body, ErrExpired
+	}
+	if !body.Pass.Schedule.Allows(now) {
+		return &body, ErrOutsideSchedule
+	}
+	if zone != "" && !slices.Contains(body.Pass.Scopes, zone) {
+		return &body, ErrScopeNotAllowed
+	}
+	return &body, nil
+}
+
+// StatusSet — проверенный и распакованный список статусов из GET /api/v1/status-list
+type StatusSet struct {
+	List StatusList
+	bits []byte
+}
+
+// VerifyStatusList проверяет подпись списка статусов и распаковывает битовую строку
+func (v *Verifier) VerifyStatusList(compact string) (*StatusSet, error) {
+	kid, payloadB, err := v.verifySignature(compact)
+	if err != nil {
+		return nil, err
+	}
+	var list StatusList
+	if err := json.Unmarshal(payloadB, &list); err != nil || list.Type != models.StatusListType {
+		return nil, ErrMalformed
+	}
+	if list.IssuerKeyID != kid {
+		return nil, ErrKeyMismatch
+	}
+	bits, err := models.DecodeStatusBits(list.EncodedList)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return &StatusSet{List: list, bits: bits}, nil
+}
+
+// Revoked сообщает, отозван или приостановлен ли пропуск; payload должен ссылаться на этот же список
+func (s *StatusSet) Revoked(p *SignedPayload) (bool, error) {
+	st := p.Pass.Status
+	if st == nil {
+		return false, ErrNoStatus
+	}
+	if st.List != s.List.ID || st.Purpose != s.List.Purpose {
+		return false, ErrStatusMismatch
+	}
+	revoked, err := models.StatusBit(s.bits, st.Index)
+	if err != nil {
+		return false, ErrStatusMismatch
+	}
+	return revoked, nil
+}
+
+// verifySignature проверяет заголовок и подпись JWS, возвращает kid и payload
+func (v *Verifier) verifySignature(compact string) (string, []byte, error) {
+	hdr, payloadB, err := crypto.ParseJWS(compact)
+	if err != nil {
+		return "", nil, ErrMalformed
+	}
+	if hdr.Alg != "EdDSA" {
+		return "", nil, ErrUnsupportedAlg
+	}
+	pub, ok := v.keys[hdr.Kid]
+	if !ok {
+		return "", nil, ErrUnknownKey
+	}
+	if err := crypto.VerifyJWS(compact, pub); err != nil {
+		return "", nil, ErrBadSignature
+	}
+	return hdr.Kid, payloadB, nil
+}
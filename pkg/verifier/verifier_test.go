@@ -0,0 +1,299 @@
+package verifier_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vbncursed/vkr/issue-service/internal/crypto"
+	"github.com/vbncursed/vkr/issue-service/internal/models"
+	"github.com/vbncursed/vkr/issue-service/pkg/verifier"
+)
+
+const (
+	testKID  = "k1"
+	testList = "org-1"
+)
+
+// понедельник, 10:00 UTC
+var testNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+type testKey struct {
+	kid  string
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+func newKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, priv: priv, pub: pub}
+}
+
+func (k testKey) jwk() verifier.JWK {
+	return verifier.JWK{Kty: "OKP", Crv: "Ed25519", Kid: k.kid, Alg: "EdDSA", X: base64.RawURLEncoding.EncodeToString(k.pub)}
+}
+
+func (k testKey) sign(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, _, err := crypto.SignJWS(k.kid, k.priv, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return compact
+}
+
+func newVerifier(t *testing.T, skew time.Duration, keys ...testKey) *verifier.Verifier {
+	t.Helper()
+	set := verifier.JWKSet{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, k.jwk())
+	}
+	v, err := verifier.New(set, verifier.Options{Skew: skew, Now: func() time.Time { return testNow }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func payload(kid string, mutate func(p *verifier.PayloadPass)) verifier.SignedPayload {
+	p := verifier.SignedPayload{
+		V: 1,
+		Pass: verifier.PayloadPass{
+			ID:     "3f1c2a9e-6b7d-4c1e-9a55-0d2b8e7f6a10",
+			Type:   "visitor",
+			Level:  "standard",
+			Scopes: []string{"lobby", "floor-3"},
+			NBF:    testNow.Add(-time.Hour),
+			EXP:    testNow.Add(time.Hour),
+			Status: &verifier.PayloadStatus{Purpose: models.StatusPurposeRevocation, List: testList, Index: 5},
+		},
+		Meta:        verifier.PayloadMeta{OrgID: testList, PolicyID: "visitors", IssuedAt: testNow.Add(-2 * time.Hour)},
+		IssuerKeyID: kid,
+	}
+	if mutate != nil {
+		mutate(&p.Pass)
+	}
+	return p
+}
+
+func TestVerify(t *testing.T) {
+	key := newKey(t, testKID)
+	other := newKey(t, "k2")
+
+	tests := []struct {
+		name    string
+		token   string
+		zone    string
+		skew    time.Duration
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			token: key.sign(t, payload(testKID, nil)),
+			zone:  "lobby",
+		},
+		{
+			name:  "valid without zone",
+			token: key.sign(t, payload(testKID, nil)),
+		},
+		{
+			name:    "unknown kid",
+			token:   other.sign(t, payload("k2", nil)),
+			wantErr: verifier.ErrUnknownKey,
+		},
+		{
+			name:    "issuer_key_id differs from kid",
+			token:   key.sign(t, payload("k2", nil)),
+			wantErr: verifier.ErrKeyMismatch,
+		},
+		{
+			name:    "not yet valid",
+			token:   key.sign(t, payload(testKID, func(p *verifier.PayloadPass) { p.NBF = testNow.Add(30 * time.Second) })),
+			wantErr: verifier.ErrNotYetValid,
+		},
+		{
+			name:  "nbf within skew",
+			token: key.sign(t, payload(testKID, func(p *verifier.PayloadPass) { p.NBF = testNow.Add(30 * time.Second) })),
+			skew:  time.Minute,
+		},
+		{
+			name:    "expired",
+			token:   key.sign(t, payload(testKID, func(p *verifier.PayloadPass) { p.EXP = testNow.Add(-30 * time.Second) })),
+			wantErr: verifier.ErrExpired,
+		},
+		{
+			name:  "exp within skew",
+			token: key.sign(t, payload(testKID, func(p *verifier.PayloadPass) { p.EXP = testNow.Add(-30 * time.Second) })),
+			skew:  time.Minute,
+		},
+		{
+			name:    "expired beyond skew",
+			token:   key.sign(t, payload(testKID, func(p *verifier.PayloadPass) { p.EXP = testNow.Add(-2 * time.Minute) })),
+			skew:    time.Minute,
+			wantErr: verifier.ErrExpired,
+		},
+		{
+			name:    "zone out of scope",
+			token:   key.sign(t, payload(testKID, nil)),
+			zone:    "server-room",
+			wantErr: verifier.ErrScopeNotAllowed,
+		},
+		{
+			name: "inside schedule",
+			token: key.sign(t, payload(testKID, func(p *verifier.PayloadPass) {
+				p.Schedule = &verifier.Schedule{TZ: "Europe/Moscow", Weekdays: []string{"mon"}, Windows: []models.ScheduleWindow{{Start: "09:00", End: "18:00"}}}
+			})),
+		},
+		{
+			name: "outside schedule window",
+			token: key.sign(t, payload(testKID, func(p *verifier.PayloadPass) {
+				p.Schedule = &verifier.Schedule{TZ: "UTC", Weekdays: []string{"mon"}, Windows: []models.ScheduleWindow{{Start: "12:00", End: "13:00"}}}
+			})),
+			wantErr: verifier.ErrOutsideSchedule,
+		},
+		{
+			name: "outside schedule weekday",
+			token: key.sign(t, payload(testKID, func(p *verifier.PayloadPass) {
+				p.Schedule = &verifier.Schedule{TZ: "UTC", Weekdays: []string{"tue"}, Windows: []models.ScheduleWindow{{Start: "09:00", End: "18:00"}}}
+			})),
+			wantErr: verifier.ErrOutsideSchedule,
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-jws",
+			wantErr: verifier.ErrMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newVerifier(t, tt.skew, key)
+			got, err := v.Verify(tt.token, tt.zone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.Pass.ID == "" {
+				t.Fatal("Verify() returned empty payload")
+			}
+		})
+	}
+}
+
+func TestVerifyTamperedSignature(t *testing.T) {
+	key := newKey(t, testKID)
+	v := newVerifier(t, 0, key)
+	token := key.sign(t, payload(testKID, nil))
+
+	i := strings.LastIndexByte(token, '.')
+	sig, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[0] ^= 0xff
+	badSig := token[:i+1] + base64.RawURLEncoding.EncodeToString(sig)
+	if _, err := v.Verify(badSig, ""); !errors.Is(err, verifier.ErrBadSignature) {
+		t.Fatalf("tampered signature: error = %v, want %v", err, verifier.ErrBadSignature)
+	}
+
+	// подмена payload при прежней подписи
+	parts := strings.Split(token, ".")
+	forged, err := json.Marshal(payload(testKID, func(p *verifier.PayloadPass) { p.Scopes = append(p.Scopes, "server-room") }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	badPayload := parts[0] + "." + base64.RawURLEncoding.EncodeToString(forged) + "." + parts[2]
+	if _, err := v.Verify(badPayload, "server-room"); !errors.Is(err, verifier.ErrBadSignature) {
+		t.Fatalf("tampered payload: error = %v, want %v", err, verifier.ErrBadSignature)
+	}
+}
+
+func TestNewRequiresUsableKey(t *testing.T) {
+	bad := verifier.JWKSet{Keys: []verifier.JWK{{Kty: "RSA", Kid: "r1", Alg: "RS256"}}}
+	if _, err := verifier.New(bad, verifier.Options{}); !errors.Is(err, verifier.ErrNoKeys) {
+		t.Fatalf("New() error = %v, want %v", err, verifier.ErrNoKeys)
+	}
+}
+
+func statusList(t *testing.T, key testKey, set ...int64) string {
+	t.Helper()
+	bits := models.NewStatusBits(0)
+	for _, i := range set {
+		if err := models.SetStatusBit(bits, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	enc, err := models.EncodeStatusBits(bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.sign(t, verifier.StatusList{
+		V:           1,
+		Type:        models.StatusListType,
+		ID:          testList,
+		Purpose:     models.StatusPurposeRevocation,
+		Size:        int64(len(bits)) * 8,
+		EncodedList: enc,
+		IssuedAt:    testNow,
+		IssuerKeyID: key.kid,
+	})
+}
+
+func TestVerifyStatusList(t *testing.T) {
+	key := newKey(t, testKID)
+	v := newVerifier(t, 0, key)
+
+	set, err := v.VerifyStatusList(statusList(t, key, 5, 42))
+	if err != nil {
+		t.Fatalf("VerifyStatusList() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		status  *verifier.PayloadStatus
+		want    bool
+		wantErr error
+	}{
+		{name: "bit set", status: &verifier.PayloadStatus{Purpose: models.StatusPurposeRevocation, List: testList, Index: 5}, want: true},
+		{name: "bit clear", status: &verifier.PayloadStatus{Purpose: models.StatusPurposeRevocation, List: testList, Index: 6}},
+		{name: "other list", status: &verifier.PayloadStatus{Purpose: models.StatusPurposeRevocation, List: "org-2", Index: 5}, wantErr: verifier.ErrStatusMismatch},
+		{name: "index out of range", status: &verifier.PayloadStatus{Purpose: models.StatusPurposeRevocation, List: testList, Index: 1 << 40}, wantErr: verifier.ErrStatusMismatch},
+		{name: "no status", wantErr: verifier.ErrNoStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := payload(testKID, func(p *verifier.PayloadPass) { p.Status = tt.status })
+			got, err := set.Revoked(&p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Revoked() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("Revoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyStatusListRejectsForeignSignature(t *testing.T) {
+	key := newKey(t, testKID)
+	other := newKey(t, "k2")
+	v := newVerifier(t, 0, key)
+
+	if _, err := v.VerifyStatusList(statusList(t, other)); !errors.Is(err, verifier.ErrUnknownKey) {
+		t.Fatalf("unknown kid: error = %v, want %v", err, verifier.ErrUnknownKey)
+	}
+	// пропуск не принимается за список статусов
+	if _, err := v.VerifyStatusList(key.sign(t, payload(testKID, nil))); !errors.Is(err, verifier.ErrMalformed) {
+		t.Fatalf("pass as status list: error = %v, want %v", err, verifier.ErrMalformed)
+	}
+}